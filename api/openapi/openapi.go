@@ -2,9 +2,12 @@ package openapi
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/fluxynet/gocipe/api"
 	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
 	"github.com/fluxynet/gocipe/util"
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -24,6 +27,17 @@ func (s *Swagger) HandlerFunc() http.HandlerFunc {
 	}
 }
 
+// AddRegistry adds every entity of the registry that is served as a resource
+func (s *Swagger) AddRegistry(reg *entity.Registry) *Swagger {
+	for _, e := range reg.Entities() {
+		if res, ok := e.(api.Resource); ok {
+			s.AddResource(ResourceFrom(res))
+		}
+	}
+
+	return s
+}
+
 func (s *Swagger) AddResources(res ...Resource) *Swagger {
 	for i := range res {
 		s.AddResource(res[i])
@@ -42,6 +56,10 @@ func (s *Swagger) AddResource(res Resource) *Swagger {
 		paramsID = openapi3.Parameters{
 			&openapi3.ParameterRef{Ref: paramID},
 		}
+		paramsRead = openapi3.Parameters{
+			&openapi3.ParameterRef{Ref: paramID},
+			&openapi3.ParameterRef{Ref: paramFields},
+		}
 	)
 
 	// no actions, skip
@@ -82,7 +100,7 @@ func (s *Swagger) AddResource(res Resource) *Swagger {
 	if actions.Has(api.ActionList) {
 		s.Paths[path].Get = &openapi3.Operation{
 			Description: "Get a list of " + name + " items",
-			Parameters:  paramsList(res.props),
+			Parameters:  paramsSearch(paramsList(res.props), res.search),
 			Responses: responsesWithErrors(openapi3.Responses{
 				statusOK: &openapi3.ResponseRef{
 					Value: &openapi3.Response{
@@ -100,10 +118,33 @@ func (s *Swagger) AddResource(res Resource) *Swagger {
 		}
 	}
 
+	if actions.Has(api.ActionList) {
+		s.Paths[path+"/_aggregate"] = &openapi3.PathItem{
+			Get: &openapi3.Operation{
+				Description: "Get aggregates of " + name + " items, grouped by fields",
+				Parameters:  paramsAggregate(res.props),
+				Responses: responsesWithErrors(openapi3.Responses{
+					statusOK: &openapi3.ResponseRef{
+						Value: &openapi3.Response{
+							Description: util.Str("OK - List of groups"),
+							Content: map[string]*openapi3.MediaType{
+								contentTypeJSON: {
+									Schema: &openapi3.SchemaRef{
+										Value: aggregateSchema(res.props),
+									},
+								},
+							},
+						},
+					},
+				}, actions),
+			},
+		}
+	}
+
 	if actions.Has(api.ActionRead) {
 		s.Paths[pathID].Get = &openapi3.Operation{
 			Description: "Get a single " + name + " by id",
-			Parameters:  paramsID,
+			Parameters:  paramsRead,
 			Responses: responsesWithErrors(openapi3.Responses{
 				statusOK: &openapi3.ResponseRef{
 					Ref: ref,
@@ -200,6 +241,7 @@ func paramsList(props Properties) []*openapi3.ParameterRef {
 		{Ref: paramLimit},
 		{Ref: paramOffset},
 		{Ref: paramSort},
+		{Ref: paramFields},
 	}
 
 	for i := range props {
@@ -219,6 +261,59 @@ func paramsList(props Properties) []*openapi3.ParameterRef {
 	return p
 }
 
+// paramsSearch adds the search parameter if fields are searchable
+func paramsSearch(p []*openapi3.ParameterRef, search []string) []*openapi3.ParameterRef {
+	if len(search) == 0 {
+		return p
+	}
+
+	return append(p, &openapi3.ParameterRef{
+		Value: &openapi3.Parameter{
+			Name:        "__q",
+			In:          "query",
+			Description: "Full-text search on " + strings.Join(search, ", ") + "; results are sorted by relevance",
+			Schema: &openapi3.SchemaRef{
+				Value: &openapi3.Schema{
+					Type: "string",
+				},
+			},
+		},
+	})
+}
+
+func paramsAggregate(props Properties) []*openapi3.ParameterRef {
+	var p = append([]*openapi3.ParameterRef{
+		{Ref: paramGroup},
+		{Ref: paramAgg},
+		{Ref: paramHaving},
+	}, paramsList(props)...)
+
+	// aggregations are neither offset nor projected
+	var params = p[:0]
+	for i := range p {
+		if p[i].Ref != paramOffset && p[i].Ref != paramFields {
+			params = append(params, p[i])
+		}
+	}
+
+	return params
+}
+
+// aggregateSchema describes groups: the fields grouped by, and aggregates which are numbers (or values of the field
+// for min and max)
+func aggregateSchema(props Properties) *openapi3.Schema {
+	var item = openapi3.NewObjectSchema()
+	item.Properties = propsToSchemas(props)
+	item.AdditionalProperties = &openapi3.SchemaRef{
+		Value: &openapi3.Schema{},
+	}
+
+	var list = openapi3.NewArraySchema()
+	list.Items = &openapi3.SchemaRef{Value: item}
+
+	return list
+}
+
 func propsToSchemas(props Properties) openapi3.Schemas {
 	var m = make(openapi3.Schemas, len(props))
 
@@ -265,21 +360,25 @@ func propToSchema(prop Property) *openapi3.Schema {
 	case types.Float64:
 		p.Type = "number"
 		p.Format = "double"
+	case types.JSON:
+		// any inline json value; type is intentionally left empty
 	}
 
 	p.Description = prop.Description
 	p.Example = prop.Example
 	p.Enum = prop.Enum
-
-	if prop.Maximum != 0 {
-		var v = float64(prop.Maximum)
-		p.Max = &v
+	p.Pattern = prop.Pattern
+
+	switch d := prop.Default.(type) {
+	case nil:
+	case fields.Static:
+		p.Default = d.V
+	default:
+		p.Description = strings.TrimSpace(p.Description + " Defaults to " + d.String() + ".")
 	}
 
-	if prop.Minimum != 0 {
-		var v = float64(prop.Minimum)
-		p.Min = &v
-	}
+	p.Max = prop.max()
+	p.Min = prop.min()
 
 	if prop.MaxLength != 0 {
 		var v = uint64(prop.MaxLength)
@@ -317,6 +416,10 @@ func responsesWithErrors(r openapi3.Responses, actions api.ActionSet) openapi3.R
 		r[statusBadRequest] = &openapi3.ResponseRef{
 			Ref: "#/components/responses/" + statusBadRequest,
 		}
+
+		r[statusConflict] = &openapi3.ResponseRef{
+			Ref: "#/components/responses/" + statusConflict,
+		}
 	}
 
 	return r
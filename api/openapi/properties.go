@@ -1,7 +1,11 @@
 package openapi
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/values/validation"
 )
 
 // Property is a field with openapi metadata
@@ -14,13 +18,37 @@ type Property struct {
 	Enum        []interface{}
 	Maximum     int
 	Minimum     int
+	Max         *float64 // precise maximum, including zero; takes precedence over Maximum
+	Min         *float64 // precise minimum, including zero; takes precedence over Minimum
 	MaxLength   int
 	MinLength   int
 	Required    bool
+	Pattern     string
+	Validators  []validation.Func
 	Items       Properties
 	Ref         string
 }
 
+// max is the upper bound of the property, nil when unbounded
+func (p Property) max() *float64 {
+	if p.Max != nil || p.Maximum == 0 {
+		return p.Max
+	}
+
+	var v = float64(p.Maximum)
+	return &v
+}
+
+// min is the lower bound of the property, nil when unbounded
+func (p Property) min() *float64 {
+	if p.Min != nil || p.Minimum == 0 {
+		return p.Min
+	}
+
+	var v = float64(p.Minimum)
+	return &v
+}
+
 // Properties is a collection of properties
 type Properties []Property
 
@@ -29,8 +57,49 @@ func (p Properties) Fields() fields.Fields {
 	var f fields.Fields
 
 	for i := range p {
-		f.Set(p[i].Name, p[i].Kind)
+		f.Add(p[i].Field)
 	}
 
 	return f
 }
+
+// Rules returns validation rules enforcing the constraints of the properties; an invalid pattern is an error, the rule
+// of its property then rejects any value
+func (p Properties) Rules() ([]validation.Rule, error) {
+	var (
+		r   = make([]validation.Rule, len(p))
+		err error
+	)
+
+	for i := range p {
+		r[i] = validation.Rule{
+			Name:      p[i].Name,
+			Kind:      p[i].Kind,
+			Required:  p[i].Required,
+			Minimum:   p[i].min(),
+			Maximum:   p[i].max(),
+			MinLength: p[i].MinLength,
+			MaxLength: p[i].MaxLength,
+			Enum:      p[i].Enum,
+			Funcs:     p[i].Validators,
+		}
+
+		if p[i].Pattern == "" {
+			continue
+		}
+
+		var e error
+		if r[i].Pattern, e = regexp.Compile(p[i].Pattern); e == nil {
+			continue
+		}
+
+		e = fmt.Errorf("pattern of %s: %w", p[i].Name, e)
+		if err == nil {
+			err = e
+		}
+
+		r[i].Funcs = append(r[i].Funcs[:len(r[i].Funcs):len(r[i].Funcs)], func(interface{}) error { return e })
+	}
+
+	return r, err
+}
@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/values"
+	"github.com/fluxynet/gocipe/values/validation"
+)
+
+func TestResource_Rules(t *testing.T) {
+	var (
+		zero float64
+		r    Resource
+	)
+
+	r.SetProperties(
+		Property{Field: fields.Field{Name: "stock", Kind: types.Int64}, Min: &zero},
+		Property{Field: fields.Field{Name: "code", Kind: types.String}, Pattern: "^[A-Z]+$"},
+	)
+
+	if r.Err() != nil {
+		t.Fatalf("Err() = %v", r.Err())
+	}
+
+	var vals = values.FromMap(map[string]interface{}{"stock": int64(-1), "code": "ab"})
+	if errs, ok := validation.Validate(vals, r.Rules(), false).(validation.Errors); !ok || len(errs) != 2 {
+		t.Errorf("Validate() = %v, want minimum of 0 and pattern enforced", errs)
+	}
+
+	r.SetProperties(Property{Field: fields.Field{Name: "code", Kind: types.String}, Pattern: "[A-Z"})
+	if r.Err() == nil {
+		t.Errorf("Err() of invalid pattern = nil")
+	}
+
+	vals = values.FromMap(map[string]interface{}{"code": "AB"})
+	if err := validation.Validate(vals, r.Rules(), false); err == nil {
+		t.Errorf("Validate() with invalid pattern = nil, want values rejected")
+	}
+}
@@ -5,8 +5,41 @@ import (
 
 	"github.com/fluxynet/gocipe/api"
 	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values/validation"
 )
 
+// ResourceFrom returns an openapi resource describing a REST resource; properties are derived from its fields unless
+// the resource is already an openapi resource
+func ResourceFrom(res api.Resource) Resource {
+	switch r := res.(type) {
+	case Resource:
+		return r
+	case *Resource:
+		return *r
+	}
+
+	var (
+		r  Resource
+		f  = res.Fields()
+		it = f.Iterator()
+	)
+
+	for it.Next() {
+		r.props = append(r.props, Property{Field: *it.Field()})
+	}
+
+	r.rules, r.err = r.props.Rules()
+
+	r.name = res.Name()
+	r.description = entity.Description(res)
+	r.actions = res.Actions()
+	r.path = strings.TrimPrefix(res.Path(), "/")
+	r.search = entity.SearchFieldsOf(res)
+
+	return r
+}
+
 // Resource is an openapi resource
 type Resource struct {
 	name        string
@@ -14,6 +47,9 @@ type Resource struct {
 	actions     api.ActionSet
 	props       Properties
 	path        string
+	search      []string
+	rules       []validation.Rule
+	err         error
 }
 
 func (r *Resource) SetName(name string) *Resource {
@@ -34,11 +70,18 @@ func (r Resource) Description() string {
 	return r.description
 }
 
+// SetProperties of the resource, compiling their validation rules; see Err
 func (r *Resource) SetProperties(props ...Property) *Resource {
 	r.props = props
+	r.rules, r.err = r.props.Rules()
 	return r
 }
 
+// Err returns the error of properties which could not be compiled into validation rules, such as an invalid pattern
+func (r Resource) Err() error {
+	return r.err
+}
+
 func (r *Resource) SetPath(path string) *Resource {
 	r.path = path
 	return r
@@ -67,3 +110,19 @@ func (r Resource) Properties() Properties {
 func (r Resource) Fields() fields.Fields {
 	return r.props.Fields()
 }
+
+// SetSearchFields sets the fields searched by full-text
+func (r *Resource) SetSearchFields(fields ...string) *Resource {
+	r.search = fields
+	return r
+}
+
+// SearchFields returns the fields searched by full-text
+func (r Resource) SearchFields() []string {
+	return r.search
+}
+
+// Rules for validating values of the resource, compiled from its properties
+func (r Resource) Rules() []validation.Rule {
+	return r.rules
+}
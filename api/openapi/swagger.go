@@ -13,6 +13,10 @@ const (
 	paramLimit  = "#/components/parameters/limit"
 	paramOffset = "#/components/parameters/offset"
 	paramSort   = "#/components/parameters/sort"
+	paramGroup  = "#/components/parameters/group"
+	paramAgg    = "#/components/parameters/agg"
+	paramHaving = "#/components/parameters/having"
+	paramFields = "#/components/parameters/fields"
 )
 
 var (
@@ -25,6 +29,7 @@ var (
 	statusUnauthorized = strconv.Itoa(http.StatusUnauthorized)
 	statusForbidden    = strconv.Itoa(http.StatusForbidden)
 	statusNotFound     = strconv.Itoa(http.StatusNotFound)
+	statusConflict     = strconv.Itoa(http.StatusConflict)
 )
 
 // Swagger is an openapi3 schema with added features
@@ -133,6 +138,66 @@ func New(info Info) *Swagger {
 				Example: "name,-age",
 			},
 		},
+
+		"fields": &openapi3.ParameterRef{
+			Value: &openapi3.Parameter{
+				ExtensionProps: openapi3.ExtensionProps{},
+				Name:           "__fields",
+				In:             "query",
+				Description:    "Comma separated list of fields to return; all fields are returned if absent",
+				Schema: &openapi3.SchemaRef{
+					Value: &openapi3.Schema{
+						Type: "string",
+					},
+				},
+				Example: "id,name",
+			},
+		},
+
+		"group": &openapi3.ParameterRef{
+			Value: &openapi3.Parameter{
+				ExtensionProps: openapi3.ExtensionProps{},
+				Name:           "__group",
+				In:             "query",
+				Description:    "Comma separated list of fields to group by",
+				Schema: &openapi3.SchemaRef{
+					Value: &openapi3.Schema{
+						Type: "string",
+					},
+				},
+				Example: "status,country",
+			},
+		},
+
+		"agg": &openapi3.ParameterRef{
+			Value: &openapi3.Parameter{
+				ExtensionProps: openapi3.ExtensionProps{},
+				Name:           "__agg",
+				In:             "query",
+				Description:    "Comma separated list of aggregates (count, sum, avg, min, max) with optional field. Results are named func_field. Defaults to count",
+				Schema: &openapi3.SchemaRef{
+					Value: &openapi3.Schema{
+						Type: "string",
+					},
+				},
+				Example: "count,sum:price",
+			},
+		},
+
+		"having": &openapi3.ParameterRef{
+			Value: &openapi3.Parameter{
+				ExtensionProps: openapi3.ExtensionProps{},
+				Name:           "__having",
+				In:             "query",
+				Description:    "Comma separated list of conditions on aggregates",
+				Schema: &openapi3.SchemaRef{
+					Value: &openapi3.Schema{
+						Type: "string",
+					},
+				},
+				Example: "count:gt:5",
+			},
+		},
 	}
 
 	swagger.Components.Responses = map[string]*openapi3.ResponseRef{
@@ -159,6 +224,12 @@ func New(info Info) *Swagger {
 				Description: util.Str("Not found"),
 			},
 		},
+
+		statusConflict: {
+			Value: &openapi3.Response{
+				Description: util.Str("Conflict. Item conflicts with an existing one"),
+			},
+		},
 	}
 
 	return &swagger
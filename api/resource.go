@@ -48,19 +48,23 @@ func (s ActionSet) NotHas(v ActionSet) bool {
 	return (s & v) == 0
 }
 
+// ResourceOpts defines how an entity is served via REST
 type ResourceOpts struct {
-	entity  entity.Entity
-	Path    string
-	Actions ActionSet
+	Entity      entity.Entity
+	Path        string
+	Description string
+	Actions     ActionSet
 }
 
+// New resource from an entity
 func New(opts ResourceOpts) Resource {
 	return resource{
-		name:    opts.entity.Name(),
-		path:    opts.Path,
-		actions: opts.Actions,
+		Entity:      opts.Entity,
+		name:        opts.Entity.Name(),
+		path:        opts.Path,
+		description: opts.Description,
+		actions:     opts.Actions,
 	}
-
 }
 
 // Resource is an entity served via REST
@@ -84,6 +88,9 @@ type resource struct {
 	// Path to the resource, omit prefix and trailing slash
 	path string
 
+	// description of the resource
+	description string
+
 	// Actions enabled
 	actions ActionSet
 }
@@ -99,3 +106,15 @@ func (r resource) Path() string {
 func (r resource) Actions() ActionSet {
 	return r.actions
 }
+
+func (r resource) Unwrap() entity.Entity {
+	return r.Entity
+}
+
+func (r resource) Description() string {
+	if r.description == "" {
+		return entity.Description(r.Entity)
+	}
+
+	return r.description
+}
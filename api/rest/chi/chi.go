@@ -6,6 +6,10 @@ import (
 	"github.com/fluxynet/gocipe/api"
 	"github.com/fluxynet/gocipe/api/rest"
 	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/webhook"
+	"github.com/fluxynet/gocipe/telemetry"
+	"github.com/fluxynet/gocipe/tenant/registry"
+	"github.com/fluxynet/gocipe/types/fields/entity"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -21,12 +25,23 @@ func GetIdFunc(r *http.Request) (string, error) {
 	return id, nil
 }
 
-// Register a series of resource definitions on a chi router
+// Register a series of resource definitions on a chi router; panics if the resource is invalid, see rest.Server.Err
 func Register(r chi.Router, db repository.Repositorium, res api.Resource) {
+	register(r, db, res, nil, nil)
+}
+
+func register(r chi.Router, db repository.Repositorium, res api.Resource, reg *entity.Registry, t *telemetry.Telemetry) {
 	var p = rest.Server{
-		IdGetter: GetIdFunc,
-		Entity:   res,
-		Repo:     db,
+		IdGetter:  GetIdFunc,
+		Entity:    res,
+		Repo:      db,
+		Actions:   res.Actions(),
+		Registry:  reg,
+		Telemetry: t,
+	}
+
+	if err := p.Err(); err != nil {
+		panic("failed to register resource " + res.Name() + ": " + err.Error())
 	}
 
 	var (
@@ -35,26 +50,78 @@ func Register(r chi.Router, db repository.Repositorium, res api.Resource) {
 	)
 
 	if actions.Has(api.ActionRead) {
-		r.Get(n+"/{id}", p.Get)
+		r.Get(n+"/{id}", p.Instrument("read", p.Get))
 	}
 
 	if actions.Has(api.ActionReplace) {
-		r.Put(n+"/{id}", p.Replace)
+		r.Put(n+"/{id}", p.Instrument("replace", p.Replace))
 	}
 
 	if actions.Has(api.ActionUpdate) {
-		r.Patch(n+"/{id}", p.Update)
+		r.Patch(n+"/{id}", p.Instrument("update", p.Update))
 	}
 
 	if actions.Has(api.ActionDelete) {
-		r.Delete(n+"/{id}", p.Delete)
+		r.Delete(n+"/{id}", p.Instrument("delete", p.Delete))
 	}
 
 	if actions.Has(api.ActionCreate) {
-		r.Post(n, p.Create)
+		r.Post(n, p.Instrument("create", p.Create))
 	}
 
 	if actions.Has(api.ActionList) {
-		r.Get(n, p.List)
+		r.Get(n, p.Instrument("list", p.List))
+		r.Get(n+"/_aggregate", p.Instrument("aggregate", p.Aggregate))
+		r.Get(n+"/_events", p.Instrument("events", p.Events))
+	}
+}
+
+// RegisterAll registers every entity of the registry that is served as a resource; panics if any of them is invalid
+func RegisterAll(r chi.Router, db repository.Repositorium, reg *entity.Registry) {
+	RegisterAllWith(r, db, reg, nil)
+}
+
+// RegisterAllWith registers every entity of the registry that is served as a resource, instrumented with telemetry
+func RegisterAllWith(r chi.Router, db repository.Repositorium, reg *entity.Registry, t *telemetry.Telemetry) {
+	for _, e := range reg.Entities() {
+		if res, ok := e.(api.Resource); ok {
+			register(r, db, res, reg, t)
+		}
+	}
+}
+
+// RegisterMetrics registers the /metrics endpoint served by the handler of the metrics library, example
+// expvar.Handler() with telemetry.Expvar, or promhttp.Handler() with a Prometheus adapter
+func RegisterMetrics(r chi.Router, h http.Handler) {
+	r.Method(http.MethodGet, "/metrics", h)
+}
+
+// RegisterTenants registers the admin endpoints managing tenants under a path, example /admin/tenants
+func RegisterTenants(r chi.Router, path string, m *registry.Manager) {
+	var t = rest.Tenants{
+		IdGetter: GetIdFunc,
+		Manager:  m,
 	}
+
+	r.Get(path, t.List)
+	r.Post(path, t.Create)
+	r.Get(path+"/{id}", t.Get)
+	r.Delete(path+"/{id}", t.Delete)
+	r.Post(path+"/{id}/suspend", t.Suspend)
+	r.Post(path+"/{id}/resume", t.Resume)
+}
+
+// RegisterWebhooks registers the endpoints managing webhook subscriptions under a path, example /admin/webhooks
+func RegisterWebhooks(r chi.Router, path string, store *webhook.Store) {
+	var h = rest.Webhooks{
+		IdGetter: GetIdFunc,
+		Store:    store,
+	}
+
+	r.Get(path, h.List)
+	r.Post(path, h.Create)
+	r.Get(path+"/{id}", h.Get)
+	r.Put(path+"/{id}", h.Replace)
+	r.Delete(path+"/{id}", h.Delete)
+	r.Get(path+"/{id}/deliveries", h.Deliveries)
 }
@@ -3,11 +3,22 @@ package rest
 import (
 	"errors"
 	"net/http"
+
+	"github.com/fluxynet/gocipe/repository"
 )
 
 var (
 	// ErrIdNotPresent indicates id is not present in a request
 	ErrIdNotPresent = errors.New("id is not present")
+
+	// ErrUnknownRelation indicates a relation requested for inclusion is not known
+	ErrUnknownRelation = errors.New("unknown relation")
+
+	// ErrNotSupported indicates the repository does not support the operation requested
+	ErrNotSupported = repository.ErrNotSupported
+
+	// ErrStreamingNotSupported indicates the response writer cannot flush, as needed to stream events
+	ErrStreamingNotSupported = errors.New("streaming not supported")
 )
 
 // GetIdFunc is a function that returns an id from an http.Request
@@ -1,16 +1,24 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fluxynet/gocipe/api"
 	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/changes"
+	"github.com/fluxynet/gocipe/telemetry"
 	"github.com/fluxynet/gocipe/types/fields/entity"
 	"github.com/fluxynet/gocipe/util"
 	"github.com/fluxynet/gocipe/values"
+	"github.com/fluxynet/gocipe/values/validation"
 )
 
 // Server represents a REST server endpoint set for a specific entity
@@ -26,36 +34,61 @@ type Server struct {
 
 	// Actions enabled
 	Actions api.ActionSet
+
+	// Registry to look up entities targeted by relations (required to include related items)
+	Registry *entity.Registry
+
+	// Telemetry of requests, see Instrument; optional
+	Telemetry *telemetry.Telemetry
+}
+
+// Err returns the error of an entity which failed to be defined, such as a resource with an invalid pattern; such an
+// entity is refused and none of its requests is served
+func (s *Server) Err() error {
+	var d interface{ Err() error }
+	if entity.As(s.Entity, &d) {
+		return d.Err()
+	}
+
+	return nil
 }
 
 // ServeHTTP is a simple muxer based on method (and also presence of id in url in case of GET)
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var (
 		disabled bool
+		action   string
 		handler  http.HandlerFunc
 	)
 
+	if err := s.Err(); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(errorBody(err))
+		return
+	}
+
 	switch r.Method {
 	case http.MethodHead, http.MethodGet:
 		if _, err := s.IdGetter(r); err == ErrIdNotPresent {
 			disabled = s.Actions.NotHas(api.ActionList)
-			handler = s.List
+			action, handler = "list", s.List
 		} else {
 			disabled = s.Actions.NotHas(api.ActionRead)
-			handler = s.Get
+			action, handler = "read", s.Get
 		}
 	case http.MethodPost:
 		disabled = s.Actions.NotHas(api.ActionCreate)
-		handler = s.Create
+		action, handler = "create", s.Create
 	case http.MethodPut:
 		disabled = s.Actions.NotHas(api.ActionReplace)
-		handler = s.Replace
+		action, handler = "replace", s.Replace
 	case http.MethodPatch:
 		disabled = s.Actions.NotHas(api.ActionUpdate)
-		handler = s.Update
+		action, handler = "update", s.Update
 	case http.MethodDelete:
 		disabled = s.Actions.NotHas(api.ActionDelete)
-		handler = s.Delete
+		action, handler = "delete", s.Delete
 	case http.MethodOptions:
 		// todo
 	}
@@ -65,10 +98,75 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	handler(w, r)
+	s.Instrument(action, handler)(w, r)
 	// todo check accepted types
 }
 
+// Instrument the handler of an action: requests are traced, counted and timed per resource, action and status, and
+// server errors logged. Incoming traces are continued by the propagation middleware of the tracing library, if any.
+// Without telemetry the handler is returned as is.
+func (s *Server) Instrument(action string, h http.HandlerFunc) http.HandlerFunc {
+	if s.Telemetry == nil {
+		return h
+	}
+
+	var (
+		t        = s.Telemetry
+		resource = s.Entity.Name()
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			rw    = &telemetry.ResponseWriter{ResponseWriter: w}
+			start = time.Now()
+		)
+
+		ctx, span := t.Start(
+			r.Context(),
+			"rest "+resource+" "+action,
+			telemetry.Attr("http.method", r.Method),
+			telemetry.Attr("http.target", r.URL.Path),
+		)
+
+		defer func() {
+			var (
+				status  = rw.Status
+				elapsed = time.Since(start)
+			)
+
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			t.Meter().Count("http_requests_total", 1, "resource", resource, "action", action, "status", strconv.Itoa(status))
+			t.Meter().Observe("http_request_duration_seconds", elapsed.Seconds(), "resource", resource, "action", action)
+
+			span.SetAttributes(telemetry.Attr("http.status_code", status))
+
+			if status >= http.StatusInternalServerError {
+				span.RecordError(errors.New(http.StatusText(status)))
+				t.Log().Error("request failed",
+					"resource", resource,
+					"action", action,
+					"status", status,
+					"duration", elapsed,
+				)
+			} else {
+				t.Log().Debug("request served",
+					"resource", resource,
+					"action", action,
+					"status", status,
+					"duration", elapsed,
+				)
+			}
+
+			span.End()
+		}()
+
+		h(rw, r.WithContext(ctx))
+	}
+}
+
 func (s *Server) Get(w http.ResponseWriter, r *http.Request) {
 	var (
 		b []byte
@@ -85,14 +183,36 @@ func (s *Server) Get(w http.ResponseWriter, r *http.Request) {
 		status = http.StatusBadRequest
 	}
 
+	var rels []entity.Relation
 	if err == nil {
-		vals, err = s.Repo.Get(ctx, s.Entity, id)
+		rels, err = s.includes(r.URL.Query())
+		if err != nil {
+			status = http.StatusBadRequest
+		}
+	}
+
+	var e entity.Entity
+	if err == nil {
+		e, err = s.projection(r.URL.Query(), rels)
+		if err != nil {
+			status = http.StatusBadRequest
+		}
 	}
 
-	if err == repository.ErrNotFound {
-		status = http.StatusNotFound
+	if err == nil {
+		vals, err = s.Repo.Get(ctx, e, id)
+	}
+
+	if err == nil && len(rels) != 0 {
+		var l = []values.Values{*vals}
+		err = s.include(ctx, rels, l)
+		vals = &l[0]
+	}
+
+	if status != http.StatusOK {
+		// already determined
 	} else if err != nil {
-		status = http.StatusInternalServerError
+		status = errorStatus(err)
 	} else {
 		var data = vals.ToMap()
 		b, err = json.Marshal(data)
@@ -103,15 +223,17 @@ func (s *Server) Get(w http.ResponseWriter, r *http.Request) {
 
 	if status == http.StatusOK {
 		w.Write(b)
+	} else if err != nil {
+		w.Write(errorBody(err))
 	}
 }
 
 func (s *Server) List(w http.ResponseWriter, r *http.Request) {
 	var (
-		b    []byte
 		err  error
 		c    []repository.Condition
 		vals []values.Values
+		cur  values.Cursor
 
 		q = r.URL.Query()
 		p = repository.Pagination{} // todo
@@ -158,14 +280,209 @@ func (s *Server) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var rels []entity.Relation
+	if err == nil {
+		rels, err = s.includes(q)
+		if err != nil {
+			status = http.StatusBadRequest
+		}
+	}
+
+	var e entity.Entity
+	if err == nil {
+		e, err = s.projection(q, rels)
+		if err != nil {
+			status = http.StatusBadRequest
+		}
+	}
+
+	if err != nil {
+		// sad
+	} else if terms := q.Get("__q"); terms != "" {
+		vals, err = repository.Search(ctx, s.Repo, e, terms, p, c...)
+	} else if len(rels) != 0 {
+		vals, err = s.Repo.List(ctx, e, p, c...)
+	} else {
+		cur, err = repository.Stream(ctx, s.Repo, e, p, c...)
+	}
+
+	if err == nil && len(rels) != 0 {
+		err = s.include(ctx, rels, vals)
+	}
+
+	if err == nil && cur == nil {
+		cur = values.NewSliceCursor(vals)
+	}
+
+	if status != http.StatusOK {
+		// already determined
+	} else if err != nil {
+		status = errorStatus(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if status == http.StatusOK {
+		writeCursor(w, cur)
+	} else {
+		w.Write(errorBody(err))
+	}
+}
+
+// writeCursor encodes records as a json list as they are read from the cursor, which is then closed. Headers having
+// been sent, errors abort the response so that clients do not mistake it for a complete list.
+func writeCursor(w http.ResponseWriter, cur values.Cursor) {
+	defer cur.Close()
+
+	w.Write([]byte("["))
+
+	for i := 0; cur.Next(); i++ {
+		var v = cur.Value()
+
+		var b, err = json.Marshal(v.ToMap())
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+
+		if i != 0 {
+			w.Write([]byte(","))
+		}
+
+		w.Write(b)
+	}
+
+	if cur.Err() != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	w.Write([]byte("]"))
+}
+
+// heartbeat is the interval of comments sent on event streams to keep idle connections open
+var heartbeat = 15 * time.Second
+
+// Events streams changes to items as server-sent events, filtered by the uri query as per List; the repository must be
+// a changes.Watcher. Clients reconnecting with a Last-Event-ID header resume after the last change they received.
+func (s *Server) Events(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		c   []repository.Condition
+		ch  <-chan changes.Change
+
+		status = http.StatusOK
+		ctx    = r.Context()
+	)
+
+	var watcher, ok = s.Repo.(changes.Watcher)
+	if !ok {
+		status = http.StatusNotImplemented
+		err = ErrNotSupported
+	}
+
+	var flusher http.Flusher
+	if err == nil {
+		if flusher, ok = w.(http.Flusher); !ok {
+			status = http.StatusInternalServerError
+			err = ErrStreamingNotSupported
+		}
+	}
+
+	if err == nil {
+		c, err = repository.ConditionsFromMap(r.URL.Query(), s.Entity.Fields())
+		if err != nil {
+			status = http.StatusBadRequest
+			err = fmt.Errorf("filters could not be parsed. %w", err)
+		}
+	}
+
+	if err == nil {
+		ch, err = watcher.Watch(ctx, s.Entity.Name(), r.Header.Get("Last-Event-ID"))
+	}
+
+	if status != http.StatusOK {
+		// already determined
+	} else if err != nil {
+		status = errorStatus(err)
+	}
+
+	if status != http.StatusOK {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(errorBody(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(status)
+	flusher.Flush()
+
+	var tick = time.NewTicker(heartbeat)
+	defer tick.Stop()
+
+	for {
+		select {
+		case change, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			if repository.Match(change.Values, c...) {
+				writeEvent(w, change)
+			}
+		case <-tick.C:
+			w.Write([]byte(": keep-alive\n\n"))
+		}
+
+		flusher.Flush()
+	}
+}
+
+// writeEvent encodes a change as a server-sent event; headers having been sent, errors abort the response
+func writeEvent(w http.ResponseWriter, c changes.Change) {
+	var b, err = json.Marshal(c.Values.ToMap())
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", c.ID, c.Type, b)
+}
+
+// Aggregate groups items as per the uri query, see repository.AggregationFromMap
+func (s *Server) Aggregate(w http.ResponseWriter, r *http.Request) {
+	var (
+		b    []byte
+		err  error
+		a    repository.Aggregation
+		vals []values.Values
+
+		status = http.StatusOK
+		ctx    = r.Context()
+	)
+
+	var agg, ok = s.Repo.(repository.Aggregator)
+	if !ok {
+		status = http.StatusNotImplemented
+		err = ErrNotSupported
+	}
+
+	if err == nil {
+		a, err = repository.AggregationFromMap(r.URL.Query(), s.Entity.Fields())
+		if err != nil {
+			status = http.StatusBadRequest
+			err = fmt.Errorf("aggregation could not be parsed. %w", err)
+		}
+	}
+
 	if err == nil {
-		vals, err = s.Repo.List(ctx, s.Entity, p, c...)
+		vals, err = agg.Aggregate(ctx, s.Entity, a)
 	}
 
-	if err == repository.ErrNotFound {
-		status = http.StatusNotFound
+	if status != http.StatusOK {
+		// already determined
 	} else if err != nil {
-		status = http.StatusInternalServerError
+		status = errorStatus(err)
 	} else {
 		var data = make([]map[string]interface{}, len(vals))
 		for i := range vals {
@@ -180,7 +497,7 @@ func (s *Server) List(w http.ResponseWriter, r *http.Request) {
 	if status == http.StatusOK {
 		w.Write(b)
 	} else {
-		w.Write([]byte(`{"error": "` + strings.ReplaceAll(err.Error(), `"`, `\"`) + `"}`))
+		w.Write(errorBody(err))
 	}
 }
 
@@ -201,10 +518,8 @@ func (s *Server) Delete(w http.ResponseWriter, r *http.Request) {
 		err = s.Repo.Delete(ctx, s.Entity, id)
 	}
 
-	if err == repository.ErrNotFound {
-		status = http.StatusNotFound
-	} else if err != nil {
-		status = http.StatusInternalServerError
+	if status == http.StatusOK && err != nil {
+		status = errorStatus(err)
 	}
 
 	w.WriteHeader(status)
@@ -219,11 +534,25 @@ func (s *Server) Create(w http.ResponseWriter, r *http.Request) {
 		ctx    = r.Context()
 	)
 
-	vals, err = values.FromJSON(r.Body, s.Entity.Fields(), false)
+	// required values are validated before missing ones are completed
+	vals, err = values.FromJSON(r.Body, s.Entity.Fields(), true)
 	defer util.Closed(r.Body, &err)
 
-	// ignore id if passed
-	vals.Unset("id")
+	if err != nil {
+		status = http.StatusBadRequest
+	} else {
+		// ignore id if passed
+		vals.Unset("id")
+		err = s.validate(vals, false)
+	}
+
+	if err == nil {
+		vals.Complete(s.Entity.Fields())
+	}
+
+	if err == nil {
+		err = repository.CheckReferences(ctx, s.Repo, s.Entity, vals)
+	}
 
 	if err == nil {
 		id, err = s.Repo.Create(ctx, s.Entity, vals)
@@ -233,13 +562,16 @@ func (s *Server) Create(w http.ResponseWriter, r *http.Request) {
 		vals.Set("id", id)
 	}
 
-	if err != nil {
-		status = http.StatusInternalServerError
+	if status == http.StatusOK && err != nil {
+		status = errorStatus(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	_, _ = w.Write(nil)
+
+	if status != http.StatusOK {
+		_, _ = w.Write(errorBody(err))
+	}
 }
 
 // Replace an entity by another instance of itself; ids cannot be updated.
@@ -257,32 +589,46 @@ func (s *Server) Replace(w http.ResponseWriter, r *http.Request) {
 		status = http.StatusBadRequest
 	}
 
-	vals, err = s.Repo.Get(ctx, s.Entity, id)
-	if err == repository.ErrNotFound {
-		status = http.StatusNotFound
+	if err == nil {
+		vals, err = s.Repo.Get(ctx, s.Entity, id)
 	}
 
 	if err == nil {
-		vals, err = values.FromJSON(r.Body, s.Entity.Fields(), false)
+		vals, err = values.FromJSON(r.Body, s.Entity.Fields(), true)
 		defer util.Closed(r.Body, &err)
+
+		if err != nil {
+			status = http.StatusBadRequest
+		}
 	}
 
 	if err == nil {
-		vals.Set("id", id)
-		err = s.Repo.Update(ctx, s.Entity, id, vals)
+		err = s.validate(vals, false)
 	}
 
-	if err == repository.ErrNotFound {
-		status = http.StatusNotFound
+	if err == nil {
+		vals.Complete(s.Entity.Fields())
 	}
 
-	if err != nil {
-		status = http.StatusInternalServerError
+	if err == nil {
+		err = repository.CheckReferences(ctx, s.Repo, s.Entity, vals)
+	}
+
+	if err == nil {
+		vals.Set("id", id)
+		err = s.Repo.Update(ctx, s.Entity, id, vals)
+	}
+
+	if status == http.StatusOK && err != nil {
+		status = errorStatus(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	_, _ = w.Write(nil)
+
+	if status != http.StatusOK {
+		_, _ = w.Write(errorBody(err))
+	}
 }
 
 // Update is partial update of an entity, typically Patch; ids cannot be updated
@@ -300,14 +646,25 @@ func (s *Server) Update(w http.ResponseWriter, r *http.Request) {
 		status = http.StatusBadRequest
 	}
 
-	vals, err = s.Repo.Get(ctx, s.Entity, id)
-	if err == repository.ErrNotFound {
-		status = http.StatusNotFound
+	if err == nil {
+		vals, err = s.Repo.Get(ctx, s.Entity, id)
 	}
 
 	if err == nil {
 		vals, err = values.FromJSON(r.Body, s.Entity.Fields(), true)
 		defer util.Closed(r.Body, &err)
+
+		if err != nil {
+			status = http.StatusBadRequest
+		}
+	}
+
+	if err == nil {
+		err = s.validate(vals, true)
+	}
+
+	if err == nil {
+		err = repository.CheckReferences(ctx, s.Repo, s.Entity, vals)
 	}
 
 	if err == nil {
@@ -315,13 +672,122 @@ func (s *Server) Update(w http.ResponseWriter, r *http.Request) {
 		err = s.Repo.Update(ctx, s.Entity, id, vals)
 	}
 
-	if err == repository.ErrNotFound {
-		status = http.StatusNotFound
-	} else if err != nil {
-		status = http.StatusInternalServerError
+	if status == http.StatusOK && err != nil {
+		status = errorStatus(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	_, err = w.Write(nil)
+
+	if status != http.StatusOK {
+		_, _ = w.Write(errorBody(err))
+	}
+}
+
+// includes returns relations requested via ?__include=name1,name2
+func (s *Server) includes(q url.Values) ([]entity.Relation, error) {
+	var v = q.Get("__include")
+	if v == "" {
+		return nil, nil
+	}
+
+	var (
+		names = strings.Split(v, ",")
+		rels  = make([]entity.Relation, len(names))
+	)
+
+	for i := range names {
+		var rel, ok = entity.RelationOf(s.Entity, names[i])
+		if !ok || s.Registry == nil || !s.Registry.Has(rel.Target) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRelation, names[i])
+		}
+
+		rels[i] = rel
+	}
+
+	return rels, nil
+}
+
+// projection returns the entity narrowed to the fields requested via __fields, along with those needed to include
+// related items; the entity as is if no fields are requested
+func (s *Server) projection(q url.Values, rels []entity.Relation) (entity.Entity, error) {
+	var v = q.Get("__fields")
+	if v == "" {
+		return s.Entity, nil
+	}
+
+	var names = strings.Split(v, ",")
+	for _, rel := range rels {
+		names = append(names, repository.LocalKey(rel))
+	}
+
+	return entity.Project(s.Entity, names...)
+}
+
+// include related items into records
+func (s *Server) include(ctx context.Context, rels []entity.Relation, records []values.Values) error {
+	for _, rel := range rels {
+		var target, _ = s.Registry.Get(rel.Target)
+
+		if err := repository.Include(ctx, s.Repo, target, rel, records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate values against the rules of the entity, if it declares any
+func (s *Server) validate(vals *values.Values, partial bool) error {
+	var c validation.Constrained
+	if !entity.As(s.Entity, &c) {
+		return nil
+	}
+
+	return validation.Validate(vals, c.Rules(), partial)
+}
+
+// errorStatus returns the http status appropriate for an error returned by a repository or validation
+func errorStatus(err error) int {
+	var verrs validation.Errors
+
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusConflict
+	case errors.As(err, &verrs), errors.Is(err, repository.ErrReferenceNotFound), errors.Is(err, repository.ErrInvalidID):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrNotSearchable), errors.Is(err, repository.ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, api.ErrInvalidRequestParameters):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrQuotaExceeded):
+		return http.StatusForbidden
+	case errors.Is(err, repository.ErrNotSupported):
+		return http.StatusNotImplemented
+	}
+
+	return http.StatusInternalServerError
+}
+
+// errorBody returns a json representation of an error; validation errors are detailed per field
+func errorBody(err error) []byte {
+	var body = struct {
+		Error   string            `json:"error"`
+		Details validation.Errors `json:"details,omitempty"`
+	}{}
+
+	if err != nil {
+		body.Error = err.Error()
+	}
+
+	if errors.As(err, &body.Details) {
+		body.Error = "validation failed"
+	}
+
+	var b, _ = json.Marshal(body)
+	return b
 }
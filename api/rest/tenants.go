@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fluxynet/gocipe/tenant/registry"
+	"github.com/fluxynet/gocipe/util"
+)
+
+// Tenants represents the admin REST endpoints managing tenants; ids are tenant names
+type Tenants struct {
+	// IdGetter to read tenant names from http.Request
+	IdGetter GetIdFunc
+
+	// Manager of tenants
+	Manager *registry.Manager
+}
+
+// List all tenants
+func (s *Tenants) List(w http.ResponseWriter, r *http.Request) {
+	var tenants, err = s.Manager.Registry.List(r.Context())
+	if err == nil && tenants == nil {
+		tenants = []registry.Tenant{}
+	}
+
+	writeJSON(w, http.StatusOK, tenants, err)
+}
+
+// Get a tenant
+func (s *Tenants) Get(w http.ResponseWriter, r *http.Request) {
+	var (
+		t         registry.Tenant
+		name, err = s.IdGetter(r)
+		ctx       = r.Context()
+	)
+
+	if err == nil {
+		t, err = s.Manager.Registry.Get(ctx, name)
+	}
+
+	writeJSON(w, http.StatusOK, t, err)
+}
+
+// Create provisions a tenant
+func (s *Tenants) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		t   registry.Tenant
+		err = json.NewDecoder(r.Body).Decode(&t)
+	)
+
+	defer util.Closed(r.Body, &err)
+
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(errorBody(err))
+		return
+	}
+
+	t.ID = ""
+	t, err = s.Manager.Provision(r.Context(), t)
+
+	writeJSON(w, http.StatusCreated, t, err)
+}
+
+// Suspend a tenant
+func (s *Tenants) Suspend(w http.ResponseWriter, r *http.Request) {
+	var (
+		t         registry.Tenant
+		name, err = s.IdGetter(r)
+	)
+
+	if err == nil {
+		t, err = s.Manager.Suspend(r.Context(), name)
+	}
+
+	writeJSON(w, http.StatusOK, t, err)
+}
+
+// Resume a suspended tenant
+func (s *Tenants) Resume(w http.ResponseWriter, r *http.Request) {
+	var (
+		t         registry.Tenant
+		name, err = s.IdGetter(r)
+	)
+
+	if err == nil {
+		t, err = s.Manager.Resume(r.Context(), name)
+	}
+
+	writeJSON(w, http.StatusOK, t, err)
+}
+
+// Delete a tenant, purging its storage
+func (s *Tenants) Delete(w http.ResponseWriter, r *http.Request) {
+	var name, err = s.IdGetter(r)
+
+	if err == nil {
+		err = s.Manager.Delete(r.Context(), name)
+	}
+
+	if err != nil {
+		writeJSON(w, 0, nil, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeJSON writes data as json with status, or err with the appropriate status
+func writeJSON(w http.ResponseWriter, status int, data interface{}, err error) {
+	var b []byte
+
+	if err == nil {
+		b, err = json.Marshal(data)
+	}
+
+	if err == ErrIdNotPresent {
+		status = http.StatusBadRequest
+	} else if err != nil {
+		status = errorStatus(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err == nil {
+		w.Write(b)
+	} else {
+		w.Write(errorBody(err))
+	}
+}
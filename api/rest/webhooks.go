@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/fluxynet/gocipe/api"
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/webhook"
+	"github.com/fluxynet/gocipe/util"
+)
+
+// Webhooks represents the REST endpoints managing webhook subscriptions; secrets are only shown on creation
+type Webhooks struct {
+	// IdGetter to read subscription ids from http.Request
+	IdGetter GetIdFunc
+
+	// Store of subscriptions
+	Store *webhook.Store
+}
+
+// List subscriptions, optionally of an entity given by ?entity=
+func (s *Webhooks) List(w http.ResponseWriter, r *http.Request) {
+	var subs, err = s.Store.List(r.Context(), r.URL.Query().Get("entity"))
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+
+	writeJSON(w, http.StatusOK, subs, err)
+}
+
+// Get a subscription
+func (s *Webhooks) Get(w http.ResponseWriter, r *http.Request) {
+	var (
+		sub     webhook.Subscription
+		id, err = s.IdGetter(r)
+	)
+
+	if err == nil {
+		sub, err = s.Store.Get(r.Context(), id)
+		sub.Secret = ""
+	}
+
+	writeJSON(w, http.StatusOK, sub, err)
+}
+
+// Create a subscription; its secret is generated unless given
+func (s *Webhooks) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		sub webhook.Subscription
+		err = json.NewDecoder(r.Body).Decode(&sub)
+	)
+
+	defer util.Closed(r.Body, &err)
+
+	if err != nil {
+		writeJSON(w, 0, nil, fmt.Errorf("%w: %s", webhook.ErrInvalidSubscription, err))
+		return
+	}
+
+	sub.ID = ""
+	sub, err = s.Store.Create(r.Context(), sub)
+
+	writeJSON(w, http.StatusCreated, sub, err)
+}
+
+// Replace a subscription; its secret is kept unless given
+func (s *Webhooks) Replace(w http.ResponseWriter, r *http.Request) {
+	var (
+		sub     webhook.Subscription
+		id, err = s.IdGetter(r)
+	)
+
+	if err == nil {
+		err = json.NewDecoder(r.Body).Decode(&sub)
+		defer util.Closed(r.Body, &err)
+
+		if err != nil {
+			err = fmt.Errorf("%w: %s", webhook.ErrInvalidSubscription, err)
+		}
+	}
+
+	if err == nil {
+		sub.ID = id
+		err = s.Store.Update(r.Context(), sub)
+		sub.Secret = ""
+	}
+
+	writeJSON(w, http.StatusOK, sub, err)
+}
+
+// Delete a subscription along with its delivery logs
+func (s *Webhooks) Delete(w http.ResponseWriter, r *http.Request) {
+	var id, err = s.IdGetter(r)
+
+	if err == nil {
+		err = s.Store.Delete(r.Context(), id)
+	}
+
+	if err != nil {
+		writeJSON(w, 0, nil, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deliveries logged for a subscription, latest first; paginated by __offset and __limit
+func (s *Webhooks) Deliveries(w http.ResponseWriter, r *http.Request) {
+	var (
+		p    repository.Pagination
+		list []webhook.Delivery
+		q    = r.URL.Query()
+		ctx  = r.Context()
+	)
+
+	var id, err = s.IdGetter(r)
+
+	if err == nil {
+		p.Offset, err = util.GetSingleInteger(q, "__offset")
+		if err == nil {
+			p.Limit, err = util.GetSingleInteger(q, "__limit")
+		}
+
+		if err != nil {
+			err = fmt.Errorf("%w: pagination could not be parsed. %s", api.ErrInvalidRequestParameters, err)
+		}
+	}
+
+	if err == nil {
+		_, err = s.Store.Get(ctx, id)
+	}
+
+	if err == nil {
+		list, err = s.Store.Deliveries(ctx, id, p)
+	}
+
+	writeJSON(w, http.StatusOK, list, err)
+}
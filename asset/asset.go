@@ -2,7 +2,6 @@ package asset
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"path"
 	"path/filepath"
@@ -10,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/fluxynet/gocipe/storage"
+	"github.com/fluxynet/gocipe/telemetry"
 	"github.com/fluxynet/gocipe/tenant"
 	"github.com/fluxynet/gocipe/util"
 	"github.com/google/uuid"
@@ -56,6 +56,20 @@ type Manager struct {
 	Storage       storage.Storage
 	UploadHandler UploadHandler
 	DeleteHandler DeleteHandler
+
+	// Usage tracks bytes stored per tenant, reserved before storing uploads; optional
+	Usage Usage
+
+	// Quota limits bytes stored per tenant, uploads beyond it are rejected; requires Usage
+	Quota Quota
+
+	// Telemetry of uploads and deletions, including bytes stored; optional
+	Telemetry *telemetry.Telemetry
+}
+
+// stored adds to the gauge of bytes stored by managers, as uploaded and deleted
+func (m Manager) stored(size int64) {
+	m.Telemetry.Meter().Gauge("asset_stored_bytes", float64(size))
 }
 
 // Serve the upload handler
@@ -74,7 +88,7 @@ func (m Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (m Manager) Create(w http.ResponseWriter, r *http.Request) {
 	var (
 		kind          = strings.TrimPrefix(r.URL.Path, m.Prefix)
-		ctx           = r.Context()
+		ctx, span     = m.Telemetry.Start(r.Context(), "asset create", telemetry.Attr("kind", kind))
 		validator, ok = m.validators[kind]
 		storageError  *StorageError
 		err           error
@@ -83,6 +97,8 @@ func (m Manager) Create(w http.ResponseWriter, r *http.Request) {
 		resCode       int
 	)
 
+	defer span.End()
+
 	if !ok {
 		w.WriteHeader(http.StatusNotFound)
 		return
@@ -96,12 +112,14 @@ func (m Manager) Create(w http.ResponseWriter, r *http.Request) {
 			var msg = strings.Replace(storageError.Message, `"`, "", -1)
 			response = []byte(`{"error": "` + msg + `"}`)
 			resCode = storageError.Code
-			fmt.Println(">>> ", resCode)
+			m.failed("asset upload failed", kind, storageError, span)
 		}
 
+		span.SetAttributes(telemetry.Attr("http.status_code", resCode), telemetry.Attr("size", asset.Size))
+
 		w.Header().Set("Content-Type", "application/json")
-		w.Write(response)
 		w.WriteHeader(resCode)
+		w.Write(response)
 	}()
 
 	var body = util.ReadAll(r.Body)
@@ -163,6 +181,22 @@ func (m Manager) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = m.reserve(ctx, int64(asset.Size)); err == ErrQuotaExceeded {
+		storageError = &StorageError{
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: err.Error(),
+		}
+
+		return
+	} else if err != nil {
+		storageError = &StorageError{
+			Code:    http.StatusInternalServerError,
+			Message: "failed to check quota: " + err.Error(),
+		}
+
+		return
+	}
+
 	if asset, err = m.store(ctx, asset, body); err != nil {
 		storageError = &StorageError{
 			Code:    http.StatusInternalServerError,
@@ -195,9 +229,12 @@ func (m Manager) store(ctx context.Context, asset Asset, d []byte) (Asset, error
 
 	var err = m.Storage.Store(ctx, filename, d)
 	if err != nil {
+		m.used(ctx, -int64(len(d))) // release the reservation
 		return asset, err
 	}
 
+	m.stored(int64(len(d)))
+
 	asset.URI = path.Join(m.BaseURL, ten, asset.ID, asset.Name)
 
 	return asset, err
@@ -209,29 +246,53 @@ func (m Manager) Delete(w http.ResponseWriter, r *http.Request) {
 		asset Asset
 		kind  string
 		err   *StorageError
-		ctx   = r.Context()
 	)
 
 	if i := strings.IndexRune(path, '/'); i != -1 {
 		kind, asset.ID = path[:i], path[i+1:]
 	}
 
+	var ctx, span = m.Telemetry.Start(r.Context(), "asset delete", telemetry.Attr("kind", kind))
+	defer span.End()
+
 	if _, ok := m.validators[kind]; !ok {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
 
+	if id, e := uuid.Parse(asset.ID); e != nil || id.String() != asset.ID {
+		err = &StorageError{
+			Code:    http.StatusBadRequest,
+			Message: "invalid asset id",
+		}
+
+		m.failed("asset deletion failed", kind, err, span)
+		w.WriteHeader(err.Code)
+		w.Write([]byte(`{"error": "` + err.Message + `"}`))
+		return
+	}
+
+	var size int64
+	if sizer, ok := m.Storage.(storage.Sizer); ok && (m.Usage != nil || m.Telemetry != nil) {
+		size, _ = sizer.Size(ctx, asset.ID)
+	}
+
 	if e := m.Storage.Delete(ctx, asset.ID); e != nil {
 		err = &StorageError{
 			Code:    http.StatusInternalServerError,
 			Message: "failed to delete file: " + e.Error(),
 		}
+	} else {
+		m.stored(-size)
+		m.used(ctx, -size)
 	}
 
 	if err != nil {
+		m.failed("asset deletion failed", kind, err, span)
+
 		var msg = strings.Replace(err.Message, `"`, "", -1)
-		w.Write([]byte(`{"error": "` + msg + `"}`))
 		w.WriteHeader(err.Code)
+		w.Write([]byte(`{"error": "` + msg + `"}`))
 		return
 	}
 
@@ -250,6 +311,17 @@ func (m Manager) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// failed logs a failure and records it on the span; client errors are logged as warnings
+func (m Manager) failed(msg, kind string, err *StorageError, span telemetry.Span) {
+	var log = m.Telemetry.Log().Error
+	if err.Code < http.StatusInternalServerError {
+		log = m.Telemetry.Log().Warn
+	}
+
+	span.RecordError(err)
+	log(msg, "kind", kind, "status", err.Code, "error", err.Message)
+}
+
 // Register a validator
 func (m *Manager) Register(kind string, validator Validator) *Manager {
 	if m.validators == nil {
@@ -0,0 +1,133 @@
+package asset
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/fluxynet/gocipe/storage"
+	"github.com/fluxynet/gocipe/tenant"
+)
+
+var (
+	// ErrQuotaExceeded when storing an asset would exceed the storage quota of the tenant
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+	// ErrUsageNotRebuildable when usage is not tracked or storage can not report sizes (see storage.Sizer)
+	ErrUsageNotRebuildable = errors.New("usage can not be rebuilt")
+)
+
+// Quota returns the maximum number of bytes a tenant may store; 0 is unlimited
+type Quota func(ctx context.Context, tenant string) (int64, error)
+
+// Usage tracks the number of bytes stored per tenant
+type Usage interface {
+	// Get bytes used by a tenant
+	Get(ctx context.Context, tenant string) (int64, error)
+
+	// Add delta (possibly negative) to the bytes used by a tenant
+	Add(ctx context.Context, tenant string, delta int64) error
+
+	// Set the bytes used by a tenant
+	Set(ctx context.Context, tenant string, n int64) error
+}
+
+// NewUsage returns an in-process usage tracker; it starts empty, see Manager.RebuildUsage
+func NewUsage() Usage {
+	return &usage{bytes: make(map[string]int64)}
+}
+
+type usage struct {
+	mu    sync.Mutex
+	bytes map[string]int64
+}
+
+func (u *usage) Get(ctx context.Context, tenant string) (int64, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.bytes[tenant], nil
+}
+
+func (u *usage) Add(ctx context.Context, tenant string, delta int64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.bytes[tenant] += delta; u.bytes[tenant] < 0 {
+		u.bytes[tenant] = 0
+	}
+
+	return nil
+}
+
+func (u *usage) Set(ctx context.Context, tenant string, n int64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.bytes[tenant] = n
+
+	return nil
+}
+
+// reserve size more bytes in the usage of the tenant of the context ahead of storing them, so that concurrent uploads
+// can not exceed the quota together; the reservation is released when it does not fit within the quota
+func (m Manager) reserve(ctx context.Context, size int64) error {
+	if m.Usage == nil {
+		return nil
+	}
+
+	var t = tenant.Get(ctx)
+
+	var err = m.Usage.Add(ctx, t, size)
+	if err != nil || m.Quota == nil {
+		return err
+	}
+
+	var limit, used int64
+	if limit, err = m.Quota(ctx, t); err == nil && limit != 0 {
+		used, err = m.Usage.Get(ctx, t)
+		if err == nil && used > limit {
+			err = ErrQuotaExceeded
+		}
+	}
+
+	if err != nil {
+		m.Usage.Add(ctx, t, -size)
+	}
+
+	return err
+}
+
+// used records a change of bytes stored by the tenant of the context
+func (m Manager) used(ctx context.Context, delta int64) error {
+	if m.Usage == nil || delta == 0 {
+		return nil
+	}
+
+	return m.Usage.Add(ctx, tenant.Get(ctx), delta)
+}
+
+// UsageOf returns the bytes stored by the tenant of the context
+func (m Manager) UsageOf(ctx context.Context) (int64, error) {
+	if m.Usage == nil {
+		return 0, nil
+	}
+
+	return m.Usage.Get(ctx, tenant.Get(ctx))
+}
+
+// RebuildUsage recomputes the bytes stored by the tenant of the context by scanning storage, which must be a
+// storage.Sizer
+func (m Manager) RebuildUsage(ctx context.Context) (int64, error) {
+	var sizer, ok = m.Storage.(storage.Sizer)
+	if !ok || m.Usage == nil {
+		return 0, ErrUsageNotRebuildable
+	}
+
+	var n, err = sizer.Size(ctx, "")
+	if err == nil {
+		err = m.Usage.Set(ctx, tenant.Get(ctx), n)
+	}
+
+	return n, err
+}
@@ -0,0 +1,93 @@
+package asset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/fluxynet/gocipe/storage/disk"
+	"github.com/fluxynet/gocipe/tenant"
+)
+
+type accept struct{}
+
+func (accept) Validate(ctx context.Context, args *ValidateArgs) error {
+	return nil
+}
+
+func TestManager_Quota(t *testing.T) {
+	var (
+		ctx = tenant.Set(context.Background(), "acme")
+		m   = Manager{
+			Prefix:  "/assets/",
+			Storage: disk.Must(t.TempDir()),
+			Usage:   NewUsage(),
+			Quota: func(ctx context.Context, tenant string) (int64, error) {
+				return 10, nil
+			},
+		}
+	)
+
+	m.Register("doc", accept{})
+
+	var upload = func(size int) (int, string) {
+		var (
+			w = httptest.NewRecorder()
+			r = httptest.NewRequest("POST", "/assets/doc?name=a.txt&resource=note&resource_id=1", bytes.NewReader(make([]byte, size)))
+		)
+
+		m.Create(w, r.WithContext(ctx))
+
+		var body struct {
+			URI string `json:"uri"`
+		}
+
+		json.Unmarshal(w.Body.Bytes(), &body)
+
+		return w.Code, path.Base(path.Dir(body.URI))
+	}
+
+	var usage = func(want int64) {
+		t.Helper()
+		if n, err := m.UsageOf(ctx); err != nil || n != want {
+			t.Errorf("UsageOf() = %d, %v, want %d", n, err, want)
+		}
+	}
+
+	var code, id = upload(6)
+	if code != http.StatusOK {
+		t.Fatalf("upload within quota status = %d", code)
+	}
+	usage(6)
+
+	if code, _ = upload(5); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("upload over quota status = %d, want %d", code, http.StatusRequestEntityTooLarge)
+	}
+	usage(6)
+
+	m.Usage.Set(ctx, "acme", 0)
+	if n, err := m.RebuildUsage(ctx); err != nil || n != 6 {
+		t.Errorf("RebuildUsage() = %d, %v, want 6", n, err)
+	}
+
+	for _, bad := range []string{"", "..", "../globex"} {
+		var w = httptest.NewRecorder()
+		if m.Delete(w, httptest.NewRequest("DELETE", "/assets/doc/"+bad, nil).WithContext(ctx)); w.Code != http.StatusBadRequest {
+			t.Errorf("delete of %q status = %d, want %d", bad, w.Code, http.StatusBadRequest)
+		}
+	}
+	usage(6)
+
+	var w = httptest.NewRecorder()
+	m.Delete(w, httptest.NewRequest("DELETE", "/assets/doc/"+id, nil).WithContext(ctx))
+	usage(0)
+
+	if code, _ = upload(10); code != http.StatusOK {
+		t.Errorf("upload after delete status = %d", code)
+	}
+	usage(10)
+}
@@ -16,18 +16,16 @@ var (
 )
 
 type resource struct {
-	repo  repository.Repositorium
-	names []repository.Named
+	repo     repository.Repositorium
+	names    []repository.Named
+	registry *entity.Registry
 }
 
 func (r *resource) Validate(ctx context.Context, args *asset.ValidateArgs) error {
-	var found bool
+	var found = r.registry != nil && r.registry.Has(args.Asset.Resource)
 
-	for i := range r.names {
-		if args.Asset.Resource == r.names[i].Name() {
-			found = true
-			break
-		}
+	for i := 0; !found && i < len(r.names); i++ {
+		found = args.Asset.Resource == r.names[i].Name()
 	}
 
 	if !found {
@@ -45,3 +43,11 @@ func Resource(repo repository.Repositorium, names ...repository.Named) asset.Val
 		names: names,
 	}
 }
+
+// Registered validator checks if a resource is registered and the id exists
+func Registered(repo repository.Repositorium, registry *entity.Registry) asset.Validator {
+	return &resource{
+		repo:     repo,
+		registry: registry,
+	}
+}
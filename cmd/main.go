@@ -28,5 +28,7 @@ func main() {
 		},
 	)
 
+	cmdRoot.AddCommand(cmdSchema(), cmdMigrate())
+
 	cmdRoot.Execute()
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/spf13/cobra"
+
+	"github.com/fluxynet/gocipe/repository/mysql/migrate"
+)
+
+var (
+	// ErrNoDSN is when a command needs a database connection but no dsn is provided
+	ErrNoDSN = errors.New("dsn required: use --dsn or GOCIPE_DSN")
+)
+
+// snapshotFile is where the schema is recorded after each diff, within the migrations directory
+const snapshotFile = "schema.json"
+
+func cmdMigrate() *cobra.Command {
+	var (
+		dir      string
+		dsn      string
+		filename string
+		name     string
+		steps    int
+		snapshot bool
+	)
+
+	var open = func() (*sql.DB, error) {
+		if dsn == "" {
+			dsn = os.Getenv("GOCIPE_DSN")
+		}
+
+		if dsn == "" {
+			return nil, ErrNoDSN
+		}
+
+		return sql.Open("mysql", dsn)
+	}
+
+	var cmdUp = &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var migrations, err = migrate.Load(dir)
+			if err != nil {
+				return err
+			}
+
+			var db *sql.DB
+			if db, err = open(); err != nil {
+				return err
+			}
+			defer db.Close()
+
+			var done []migrate.Migration
+			done, err = migrate.Migrator{DB: db}.Up(cmd.Context(), migrations)
+			for _, m := range done {
+				fmt.Fprintf(cmd.OutOrStdout(), "applied  %s_%s\n", m.Version, m.Name)
+			}
+
+			return err
+		},
+	}
+
+	var cmdDown = &cobra.Command{
+		Use:   "down",
+		Short: "Revert the latest applied migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var migrations, err = migrate.Load(dir)
+			if err != nil {
+				return err
+			}
+
+			var db *sql.DB
+			if db, err = open(); err != nil {
+				return err
+			}
+			defer db.Close()
+
+			var done []migrate.Migration
+			done, err = migrate.Migrator{DB: db}.Down(cmd.Context(), migrations, steps)
+			for _, m := range done {
+				fmt.Fprintf(cmd.OutOrStdout(), "reverted %s_%s\n", m.Version, m.Name)
+			}
+
+			return err
+		},
+	}
+
+	var cmdStatus = &cobra.Command{
+		Use:   "status",
+		Short: "Show applied and pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var migrations, err = migrate.Load(dir)
+			if err != nil {
+				return err
+			}
+
+			var db *sql.DB
+			if db, err = open(); err != nil {
+				return err
+			}
+			defer db.Close()
+
+			var status []migrate.Status
+			if status, err = (migrate.Migrator{DB: db}).Status(cmd.Context(), migrations); err != nil {
+				return err
+			}
+
+			for _, s := range status {
+				var applied = "pending"
+				if s.AppliedAt != nil {
+					applied = s.AppliedAt.Format(time.RFC3339)
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "%-25s %s_%s\n", applied, s.Version, s.Name)
+			}
+
+			return nil
+		},
+	}
+
+	var cmdDiff = &cobra.Command{
+		Use:   "diff",
+		Short: "Generate a migration from differences between entities and the live schema (or the last snapshot)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var reg, err = loadEntities(filename)
+			if err != nil {
+				return err
+			}
+
+			// tables are only dropped if managed, as recorded by the last snapshot
+			var managed, current migrate.Schema
+			if managed, err = migrate.LoadSnapshot(filepath.Join(dir, snapshotFile)); err != nil {
+				return err
+			}
+
+			if snapshot || (dsn == "" && os.Getenv("GOCIPE_DSN") == "") {
+				current = managed
+			} else {
+				var db *sql.DB
+				if db, err = open(); err != nil {
+					return err
+				}
+				defer db.Close()
+
+				current, err = migrate.Inspect(cmd.Context(), db)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			var (
+				target   = migrate.FromEntities(reg.Entities())
+				up, down = migrate.Diff(current, target, managed)
+				m        migrate.Migration
+			)
+
+			m, err = migrate.Generate(dir, name, up, down, time.Now())
+			if err == migrate.ErrNoChanges {
+				fmt.Fprintln(cmd.OutOrStdout(), "schema is up to date")
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "generated %s_%s\n", m.Version, m.Name)
+
+			return migrate.SaveSnapshot(filepath.Join(dir, snapshotFile), target)
+		},
+	}
+
+	cmdDown.Flags().IntVarP(&steps, "steps", "n", 1, "number of migrations to revert")
+	cmdDiff.Flags().StringVarP(&filename, "file", "f", "entities.json", "json file containing entity definitions")
+	cmdDiff.Flags().StringVar(&name, "name", "migration", "name of the migration (letters, digits and underscores)")
+	cmdDiff.Flags().BoolVar(&snapshot, "snapshot", false, "compare with the last snapshot instead of the live schema")
+
+	var cmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Versioned schema migrations (mysql)",
+	}
+
+	cmd.PersistentFlags().StringVar(&dir, "dir", "migrations", "directory containing migration files")
+	cmd.PersistentFlags().StringVar(&dsn, "dsn", "", "mysql dsn, defaults to GOCIPE_DSN environment variable")
+	cmd.AddCommand(cmdUp, cmdDown, cmdStatus, cmdDiff)
+
+	return cmd
+}
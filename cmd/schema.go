@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/fluxynet/gocipe/repository/mongo"
+	"github.com/fluxynet/gocipe/repository/mysql"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+var (
+	// ErrUnknownDialect is when an unsupported schema dialect is requested
+	ErrUnknownDialect = errors.New("unknown dialect")
+)
+
+// loadEntities reads entity specs from a json file; a decoding error prevails over the error of closing the file
+func loadEntities(filename string) (*entity.Registry, error) {
+	var f, err = os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var reg *entity.Registry
+	reg, err = entity.Decode(f)
+
+	if cerr := f.Close(); err == nil && cerr != nil {
+		return nil, cerr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reg, nil
+}
+
+// printSchema writes the schema definition of entities in a dialect (mysql or mongo)
+func printSchema(w io.Writer, reg *entity.Registry, dialect string) error {
+	for _, e := range reg.Entities() {
+		switch dialect {
+		default:
+			return fmt.Errorf("%w: %s", ErrUnknownDialect, dialect)
+		case "mysql":
+			fmt.Fprintf(w, "%s\n\n", mysql.CreateTable(e))
+		case "mongo":
+			for _, cmd := range []bson.D{mongo.CreateCollection(e), mongo.CreateIndexes(e)} {
+				if cmd == nil {
+					continue
+				}
+
+				var b, err = bson.MarshalExtJSON(cmd, false, false)
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(w, "db.runCommand(%s);\n", b)
+			}
+
+			fmt.Fprintln(w)
+		}
+	}
+
+	return nil
+}
+
+func cmdSchema() *cobra.Command {
+	var (
+		filename string
+		dialect  string
+	)
+
+	var cmdPrint = &cobra.Command{
+		Use:   "print",
+		Short: "Print schema definitions (CREATE TABLE or mongo commands) for entities",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var reg, err = loadEntities(filename)
+			if err != nil {
+				return err
+			}
+
+			return printSchema(cmd.OutOrStdout(), reg, dialect)
+		},
+	}
+
+	cmdPrint.Flags().StringVarP(&filename, "file", "f", "entities.json", "json file containing entity definitions")
+	cmdPrint.Flags().StringVarP(&dialect, "dialect", "d", "mysql", "schema dialect: mysql or mongo")
+
+	var cmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Schema generation from entity definitions",
+	}
+
+	cmd.AddCommand(cmdPrint)
+
+	return cmd
+}
@@ -5,6 +5,7 @@ go 1.15
 require (
 	github.com/getkin/kin-openapi v0.53.0
 	github.com/go-chi/chi/v5 v5.0.0
+	github.com/go-sql-driver/mysql v1.5.0
 	github.com/google/uuid v1.2.0
 	github.com/spf13/cobra v1.1.1
 	go.mongodb.org/mongo-driver v1.4.6
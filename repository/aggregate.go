@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/util"
+	"github.com/fluxynet/gocipe/values"
+)
+
+var (
+	// ErrInvalidAggregate when an aggregate function is unknown or not applicable to the attribute
+	ErrInvalidAggregate = errors.New("invalid aggregate")
+)
+
+// AggregateFunc is a function computed over the items of a group
+type AggregateFunc uint8
+
+const (
+	// Count the number of items, or of non-null values if an attribute is specified
+	Count = AggregateFunc(0)
+
+	// Sum of values of a numeric attribute
+	Sum = AggregateFunc(1)
+
+	// Avg is the average of values of a numeric attribute
+	Avg = AggregateFunc(2)
+
+	// Min is the smallest value of an attribute
+	Min = AggregateFunc(3)
+
+	// Max is the largest value of an attribute
+	Max = AggregateFunc(4)
+)
+
+// String representation, also used in query strings and default aliases
+func (a AggregateFunc) String() string {
+	switch a {
+	case Count:
+		return "count"
+	case Sum:
+		return "sum"
+	case Avg:
+		return "avg"
+	case Min:
+		return "min"
+	case Max:
+		return "max"
+	}
+
+	return "?? " + strconv.Itoa(int(a))
+}
+
+// Aggregate is a function applied to an attribute
+type Aggregate struct {
+	Func      AggregateFunc
+	Attribute string
+
+	// Alias is the name of the result; defaults to func_attribute, example sum_price (or count)
+	Alias string
+}
+
+// Name of the result of the aggregate
+func (a Aggregate) Name() string {
+	if a.Alias != "" {
+		return a.Alias
+	} else if a.Attribute == "" {
+		return a.Func.String()
+	}
+
+	return a.Func.String() + "_" + a.Attribute
+}
+
+// Aggregation describes a group-by query
+type Aggregation struct {
+	// GroupBy attributes; all items form a single group if empty
+	GroupBy []string
+
+	// Aggregates computed for each group
+	Aggregates []Aggregate
+
+	// Conditions filter items before grouping
+	Conditions []Condition
+
+	// Having filters groups; attributes refer to aggregate names
+	Having []Condition
+
+	// Order of groups; attributes refer to group-by attributes or aggregate names
+	Order []OrderBy
+
+	// Limit the number of groups returned
+	Limit int
+}
+
+// Aggregator is a repository able to compute aggregations
+type Aggregator interface {
+	// Aggregate items of an entity; each resulting Values holds the group-by attributes and aggregate names
+	Aggregate(ctx context.Context, entity entity.Entity, a Aggregation) ([]values.Values, error)
+}
+
+// AggregatesFromString parses a comma separated list of aggregates of the form func[:attribute], example
+// count,sum:price,avg:price
+func AggregatesFromString(s string, f fields.Fields) ([]Aggregate, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var (
+		p    = strings.Split(s, ",")
+		aggs = make([]Aggregate, 0, len(p))
+	)
+
+	for i := range p {
+		var (
+			a          Aggregate
+			name, attr = p[i], ""
+		)
+
+		if c := strings.Index(name, ":"); c != -1 {
+			name, attr = name[:c], name[c+1:]
+		}
+
+		switch name {
+		case "count":
+			a.Func = Count
+		case "sum":
+			a.Func = Sum
+		case "avg":
+			a.Func = Avg
+		case "min":
+			a.Func = Min
+		case "max":
+			a.Func = Max
+		default:
+			return nil, ErrInvalidAggregate
+		}
+
+		a.Attribute = attr
+		if err := a.check(f); err != nil {
+			return nil, err
+		}
+
+		aggs = append(aggs, a)
+	}
+
+	return aggs, nil
+}
+
+// check that the attribute of an aggregate exists and suits the function
+func (a Aggregate) check(f fields.Fields) error {
+	if a.Attribute == "" {
+		if a.Func == Count {
+			return nil
+		}
+
+		return ErrInvalidAggregate
+	}
+
+	if !f.Contains(a.Attribute) {
+		return ErrInvalidAttribute
+	}
+
+	var kind = f.TypeOf(a.Attribute)
+
+	switch a.Func {
+	case Sum, Avg:
+		if kind != types.Int64 && kind != types.Float64 {
+			return ErrInvalidAggregate
+		}
+	case Min, Max:
+		if kind == types.JSON || kind == types.Bool {
+			return ErrInvalidAggregate
+		}
+	}
+
+	return nil
+}
+
+// AggregationFromMap reads an aggregation from a map of key => values (typically from uri query). __group is a comma
+// separated list of attributes, __agg a list of aggregates (see AggregatesFromString, defaults to count), __having a list
+// of conditions on aggregates of the form name:op:value (example count:gt:5), __sort a list of group-by attributes or
+// aggregate names (prefixed with - for descending order) and __limit the maximum number of groups. Other keys filter
+// items as per ConditionsFromMap.
+func AggregationFromMap(m map[string][]string, f fields.Fields) (Aggregation, error) {
+	var (
+		a   Aggregation
+		err error
+	)
+
+	a.Conditions, err = ConditionsFromMap(m, f)
+	if err != nil {
+		return a, err
+	}
+
+	if g := single(m, "__group"); g != "" {
+		a.GroupBy = strings.Split(g, ",")
+	}
+
+	for _, attr := range a.GroupBy {
+		if !f.Contains(attr) {
+			return a, ErrInvalidAttribute
+		}
+	}
+
+	a.Aggregates, err = AggregatesFromString(single(m, "__agg"), f)
+	if err != nil {
+		return a, err
+	}
+
+	if len(a.Aggregates) == 0 {
+		a.Aggregates = []Aggregate{{Func: Count}}
+	}
+
+	var names = make(map[string]bool, len(a.GroupBy)+len(a.Aggregates))
+	for _, attr := range a.GroupBy {
+		names[attr] = false
+	}
+
+	for _, agg := range a.Aggregates {
+		names[agg.Name()] = true
+	}
+
+	a.Having, err = havingFromString(single(m, "__having"), names)
+	if err != nil {
+		return a, err
+	}
+
+	if s := single(m, "__sort"); s != "" {
+		for _, attr := range strings.Split(s, ",") {
+			var o = OrderBy{Attribute: attr}
+			if strings.HasPrefix(attr, "-") {
+				o = OrderBy{Attribute: attr[1:], Sort: Descending}
+			}
+
+			if _, ok := names[o.Attribute]; !ok {
+				return a, ErrUnknownSortAttribute
+			}
+
+			a.Order = append(a.Order, o)
+		}
+	}
+
+	a.Limit, err = util.GetSingleInteger(m, "__limit")
+
+	return a, err
+}
+
+// havingFromString parses conditions on aggregates; names maps result names to whether they are aggregates
+func havingFromString(s string, names map[string]bool) ([]Condition, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var (
+		p     = strings.Split(s, ",")
+		conds = make([]Condition, 0, len(p))
+	)
+
+	for i := range p {
+		var c = strings.Index(p[i], ":")
+		if c == -1 {
+			return nil, ErrInvalidConditionOperator
+		}
+
+		var name, w = p[i][:c], p[i][c+1:]
+		if !names[name] {
+			return nil, ErrInvalidAttribute
+		}
+
+		var op, v, err = operatorFromString(w, types.Float64)
+		if err != nil {
+			return nil, err
+		}
+
+		var f float64
+		f, err = types.Float64FromString(v)
+		if err != nil {
+			return nil, err
+		}
+
+		conds = append(conds, Condition{Attribute: name, Operator: op, Value: f})
+	}
+
+	return conds, nil
+}
+
+// single returns the value of a key if present exactly once
+func single(m map[string][]string, key string) string {
+	if v := m[key]; len(v) == 1 {
+		return v[0]
+	}
+
+	return ""
+}
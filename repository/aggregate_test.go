@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+)
+
+func TestAggregationFromMap(t *testing.T) {
+	var f = fields.From(
+		fields.Field{Name: "status", Kind: types.String},
+		fields.Field{Name: "active", Kind: types.Bool},
+		fields.Field{Name: "price", Kind: types.Float64},
+	)
+
+	tests := []struct {
+		name    string
+		m       map[string][]string
+		want    Aggregation
+		wantErr error
+	}{
+		{
+			name: "Defaults to count",
+			m:    map[string][]string{"__group": {"status"}},
+			want: Aggregation{
+				GroupBy:    []string{"status"},
+				Aggregates: []Aggregate{{Func: Count}},
+			},
+		},
+		{
+			name: "Complete",
+			m: map[string][]string{
+				"status":   {"ne:deleted"},
+				"__group":  {"status"},
+				"__agg":    {"count,sum:price,max:price"},
+				"__having": {"sum_price:gte:100"},
+				"__sort":   {"-count,status"},
+				"__limit":  {"10"},
+			},
+			want: Aggregation{
+				GroupBy: []string{"status"},
+				Aggregates: []Aggregate{
+					{Func: Count},
+					{Func: Sum, Attribute: "price"},
+					{Func: Max, Attribute: "price"},
+				},
+				Conditions: []Condition{{Attribute: "status", Operator: NotEquals, Value: "deleted"}},
+				Having:     []Condition{{Attribute: "sum_price", Operator: GreaterOrEqualTo, Value: float64(100)}},
+				Order:      []OrderBy{{Attribute: "count", Sort: Descending}, {Attribute: "status"}},
+				Limit:      10,
+			},
+		},
+		{
+			name:    "Unknown group attribute",
+			m:       map[string][]string{"__group": {"country"}},
+			wantErr: ErrInvalidAttribute,
+		},
+		{
+			name:    "Sum of non numeric",
+			m:       map[string][]string{"__agg": {"sum:status"}},
+			wantErr: ErrInvalidAggregate,
+		},
+		{
+			name:    "Unknown function",
+			m:       map[string][]string{"__agg": {"median:price"}},
+			wantErr: ErrInvalidAggregate,
+		},
+		{
+			name:    "Having on group attribute",
+			m:       map[string][]string{"__group": {"status"}, "__having": {"status:eq:1"}},
+			wantErr: ErrInvalidAttribute,
+		},
+		{
+			name:    "Unknown sort",
+			m:       map[string][]string{"__sort": {"price"}},
+			wantErr: ErrUnknownSortAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, err = AggregationFromMap(tt.m, f)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AggregationFromMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got.GroupBy, tt.want.GroupBy) {
+				t.Errorf("GroupBy got = %v, want %v", got.GroupBy, tt.want.GroupBy)
+			}
+
+			if !reflect.DeepEqual(got.Aggregates, tt.want.Aggregates) {
+				t.Errorf("Aggregates got = %v, want %v", got.Aggregates, tt.want.Aggregates)
+			}
+
+			if got.Limit != tt.want.Limit {
+				t.Errorf("Limit got = %d, want %d", got.Limit, tt.want.Limit)
+			}
+
+			compareConditions(t, got.Conditions, tt.want.Conditions)
+			compareConditions(t, got.Having, tt.want.Having)
+			compareOrderBys(t, got.Order, tt.want.Order)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ repository.Repositorium = &Repo{}
+	var _ repository.Aggregator = &Repo{}
+	var _ repository.Searcher = &Repo{}
+	var _ repository.Streamer = &Repo{}
+	var _ repository.Counter = &Repo{}
+}
+
+// sep separates the parts of keys
+const sep = "\x00"
+
+// Repo is a repository caching the results of Get and List of another repository. Keys include the tenant of the
+// context, and any write to an entity invalidates its cached results for that tenant: results are cached under a
+// generation of the entity, which is replaced on write. Generations are held in the cache itself so that a cache shared
+// by several processes is invalidated for all of them.
+type Repo struct {
+	repo     repository.Repositorium
+	cache    Cache
+	ttl      time.Duration
+	entities map[string]bool
+}
+
+// New repository caching results for ttl; only results of the named entities are cached, or of all if none is named
+func New(repo repository.Repositorium, c Cache, ttl time.Duration, entities ...string) *Repo {
+	var r = Repo{
+		repo:     repo,
+		cache:    c,
+		ttl:      ttl,
+		entities: make(map[string]bool, len(entities)),
+	}
+
+	for _, name := range entities {
+		r.entities[name] = true
+	}
+
+	return &r
+}
+
+// cached checks if results of an entity are cached
+func (r *Repo) cached(name string) bool {
+	return len(r.entities) == 0 || r.entities[name]
+}
+
+// generation of the cached results of an entity for the tenant of the context
+func (r *Repo) generation(ctx context.Context, name string) string {
+	if g, ok := r.cache.Get("gen" + sep + tenant.Get(ctx) + sep + name); ok {
+		if s, ok := g.(string); ok {
+			return s
+		}
+	}
+
+	return r.invalidate(ctx, name)
+}
+
+// invalidate cached results of an entity for the tenant of the context by starting a new generation; the generation
+// is never reused so that results cached under an evicted generation can not reappear
+func (r *Repo) invalidate(ctx context.Context, name string) string {
+	var g = strconv.FormatInt(time.Now().UnixNano(), 36)
+	r.cache.Set("gen"+sep+tenant.Get(ctx)+sep+name, g, 0)
+	return g
+}
+
+// written invalidates cached results of an entity after a write
+func (r *Repo) written(ctx context.Context, name string) {
+	if r.cached(name) {
+		r.invalidate(ctx, name)
+	}
+}
+
+// key of a cached result of an entity
+func (r *Repo) key(ctx context.Context, e entity.Entity, parts ...string) string {
+	var name = e.Name()
+
+	return strings.Join(append([]string{tenant.Get(ctx), name, r.generation(ctx, name), e.Fields().String()}, parts...), sep)
+}
+
+// ListKey returns a normalized representation of pagination and conditions; conditions are sorted as their order does
+// not affect results
+func ListKey(p repository.Pagination, c ...repository.Condition) string {
+	var (
+		conds = make([]string, len(c))
+		order = make([]string, len(p.Order))
+	)
+
+	for i := range c {
+		conds[i] = fmt.Sprintf("%s %d %d %T %v", c[i].Attribute, c[i].Operator, c[i].Type, c[i].Value, c[i].Value)
+	}
+
+	for i := range p.Order {
+		order[i] = p.Order[i].Attribute + " " + strconv.Itoa(int(p.Order[i].Sort))
+	}
+
+	sort.Strings(conds)
+
+	return strconv.Itoa(p.Offset) + sep + strconv.Itoa(p.Limit) + sep + strings.Join(order, ",") + sep + strings.Join(conds, sep)
+}
+
+// Get a single Name by id, from the cache if available
+func (r *Repo) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	if !r.cached(entity.Name()) {
+		return r.repo.Get(ctx, entity, id)
+	}
+
+	var key = r.key(ctx, entity, "get", id)
+
+	if v, ok := r.cache.Get(key); ok {
+		if vals, ok := v.(*values.Values); ok {
+			return vals.Clone(), nil
+		}
+	}
+
+	var vals, err = r.repo.Get(ctx, entity, id)
+	if err == nil {
+		r.cache.Set(key, vals.Clone(), r.ttl)
+	}
+
+	return vals, err
+}
+
+// List multiple Name with pagination rules and conditions, from the cache if available
+func (r *Repo) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	if !r.cached(entity.Name()) {
+		return r.repo.List(ctx, entity, p, c...)
+	}
+
+	var key = r.key(ctx, entity, "list", ListKey(p, c...))
+
+	if v, ok := r.cache.Get(key); ok {
+		if l, ok := v.([]values.Values); ok {
+			return clone(l), nil
+		}
+	}
+
+	var l, err = r.repo.List(ctx, entity, p, c...)
+	if err == nil {
+		r.cache.Set(key, clone(l), r.ttl)
+	}
+
+	return l, err
+}
+
+// Count Name satisfying conditions, from the cache if available
+func (r *Repo) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	if !r.cached(entity.Name()) {
+		return repository.CountOf(ctx, r.repo, entity, c...)
+	}
+
+	var key = r.key(ctx, entity, "count", ListKey(repository.Pagination{}, c...))
+
+	if v, ok := r.cache.Get(key); ok {
+		if n, ok := v.(int64); ok {
+			return n, nil
+		}
+	}
+
+	var n, err = repository.CountOf(ctx, r.repo, entity, c...)
+	if err == nil {
+		r.cache.Set(key, n, r.ttl)
+	}
+
+	return n, err
+}
+
+// clone a list of records so that cached ones are not modified by callers
+func clone(l []values.Values) []values.Values {
+	if l == nil {
+		return nil
+	}
+
+	var c = make([]values.Values, len(l))
+	for i := range l {
+		c[i] = *l[i].Clone()
+	}
+
+	return c
+}
+
+// Stream multiple Name with pagination rules and conditions; not cached
+func (r *Repo) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	return repository.Stream(ctx, r.repo, entity, p, c...)
+}
+
+// Search items matching terms; not cached
+func (r *Repo) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	return repository.Search(ctx, r.repo, entity, terms, p, c...)
+}
+
+// Aggregate items of an entity; not cached
+func (r *Repo) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	if agg, ok := r.repo.(repository.Aggregator); ok {
+		return agg.Aggregate(ctx, entity, a)
+	}
+
+	return nil, repository.ErrNotSupported
+}
+
+// Delete a single Name by id
+func (r *Repo) Delete(ctx context.Context, named repository.Named, id string) error {
+	defer r.written(ctx, named.Name())
+	return r.repo.Delete(ctx, named, id)
+}
+
+// DeleteWhere delete multiple Name based on conditions
+func (r *Repo) DeleteWhere(ctx context.Context, named repository.Named, c ...repository.Condition) error {
+	defer r.written(ctx, named.Name())
+	return r.repo.DeleteWhere(ctx, named, c...)
+}
+
+// Create a new Entity in persistent storage
+func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	defer r.written(ctx, named.Name())
+	return r.repo.Create(ctx, named, vals)
+}
+
+// Update an existing Name in persistent storage
+func (r *Repo) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	defer r.written(ctx, named.Name())
+	return r.repo.Update(ctx, named, id, vals)
+}
+
+// UpdateWhere Values in persistent storage
+func (r *Repo) UpdateWhere(ctx context.Context, named repository.Named, vals *values.Values, c ...repository.Condition) error {
+	defer r.written(ctx, named.Name())
+	return r.repo.UpdateWhere(ctx, named, vals, c...)
+}
+
+// Close connection to the underlying repository
+func (r *Repo) Close() error {
+	return r.repo.Close()
+}
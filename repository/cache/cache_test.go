@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// counting is a repository counting reads, returning the same records
+type counting struct {
+	repository.Repositorium
+	reads int
+}
+
+func (c *counting) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	c.reads++
+	return values.FromMap(map[string]interface{}{"id": id, "tenant": tenant.Get(ctx)}), nil
+}
+
+func (c *counting) List(ctx context.Context, entity entity.Entity, p repository.Pagination, cond ...repository.Condition) ([]values.Values, error) {
+	c.reads++
+	return []values.Values{*values.FromMap(map[string]interface{}{"id": "1"})}, nil
+}
+
+func (c *counting) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	return nil
+}
+
+func TestLRU(t *testing.T) {
+	var (
+		c   = NewLRU(2)
+		now = time.Now()
+	)
+
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1, 0)
+	c.Set("b", 2, time.Minute)
+	c.Get("a")
+	c.Set("c", 3, 0) // evicts b, least recently used
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(b) found evicted value")
+	}
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %v, %v", v, ok)
+	}
+
+	c.Set("d", 4, time.Minute)
+	now = now.Add(2 * time.Minute)
+
+	if _, ok := c.Get("d"); ok {
+		t.Errorf("Get(d) found expired value")
+	}
+
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+}
+
+func TestRepo(t *testing.T) {
+	var (
+		backend = &counting{}
+		r       = New(backend, NewLRU(100), time.Minute, "country")
+		country = entity.Partial("country", fields.From(fields.Field{Name: "id", Kind: types.String}))
+		plan    = entity.Partial("plan", fields.From(fields.Field{Name: "id", Kind: types.String}))
+		foo     = tenant.Set(context.Background(), "foo")
+		bar     = tenant.Set(context.Background(), "bar")
+		p       = repository.Pagination{Limit: 10}
+		a       = repository.Condition{Attribute: "a", Value: "1"}
+		b       = repository.Condition{Attribute: "b", Value: int64(1)}
+	)
+
+	var reads = func(want int) {
+		t.Helper()
+		if backend.reads != want {
+			t.Errorf("reads = %d, want %d", backend.reads, want)
+		}
+	}
+
+	var v, _ = r.Get(foo, country, "mu")
+	v.Set("name", "modified by caller")
+	v, _ = r.Get(foo, country, "mu")
+	reads(1)
+
+	if v.Get("name") != nil {
+		t.Errorf("cached value modified by caller")
+	}
+
+	if v, _ = r.Get(bar, country, "mu"); v.Get("tenant").String() != "bar" {
+		t.Errorf("Get() returned value cached for another tenant")
+	}
+	reads(2)
+
+	r.List(foo, country, p, a, b)
+	r.List(foo, country, p, b, a)
+	reads(3)
+
+	r.Update(foo, country, "mu", nil)
+	r.Get(foo, country, "mu")
+	r.List(foo, country, p, a, b)
+	reads(5)
+
+	r.Get(bar, country, "mu") // other tenant not invalidated
+	reads(5)
+
+	r.Get(foo, plan, "basic")
+	r.Get(foo, plan, "basic")
+	reads(7)
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache stores values by key, for a limited time
+type Cache interface {
+	// Get a value; false if absent or expired
+	Get(key string) (interface{}, bool)
+
+	// Set a value for a duration; a zero ttl means the value does not expire
+	Set(key string, value interface{}, ttl time.Duration)
+
+	// Delete a value
+	Delete(key string)
+}
+
+// LRU is an in-process cache holding a maximum number of values, evicting the least recently used ones first.
+// It is safe for concurrent use.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type entry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// NewLRU returns an LRU cache holding at most capacity values
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get a value; false if absent or expired
+func (c *LRU) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var el, ok = c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	var e = el.Value.(*entry)
+	if !e.expires.IsZero() && c.now().After(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return e.value, true
+}
+
+// Set a value for a duration; a zero ttl means the value does not expire
+func (c *LRU) Set(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		el.Value = &entry{key: key, value: value, expires: expires}
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expires: expires})
+
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete a value
+func (c *LRU) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// Len is the number of values held, including expired ones not yet evicted
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
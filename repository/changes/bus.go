@@ -0,0 +1,125 @@
+package changes
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/fluxynet/gocipe/tenant"
+)
+
+// Bus is an in-process source of changes. It keeps the latest changes so that watching can be resumed after one of them;
+// watching after a change no longer kept resumes with the oldest kept.
+type Bus struct {
+	mu       sync.Mutex
+	seq      uint64
+	recent   []Change
+	size     int
+	watchers map[*watcher]struct{}
+}
+
+// watcher of an entity within a tenant
+type watcher struct {
+	entity string
+	tenant string
+	ch     chan Change
+}
+
+// Buffer of changes pending delivery per watcher; watchers falling further behind are closed
+const Buffer = 64
+
+// NewBus keeping the latest size changes for resumption
+func NewBus(size int) *Bus {
+	return &Bus{size: size, watchers: make(map[*watcher]struct{})}
+}
+
+// Publish a change to watchers of its entity and tenant; its id is assigned by the bus
+func (b *Bus) Publish(c Change) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	c.ID = strconv.FormatUint(b.seq, 10)
+
+	if b.size != 0 {
+		if len(b.recent) == b.size {
+			b.recent = append(b.recent[:0], b.recent[1:]...)
+		}
+
+		b.recent = append(b.recent, c)
+	}
+
+	for w := range b.watchers {
+		if w.entity != c.Entity || w.tenant != c.Tenant {
+			continue
+		}
+
+		select {
+		case w.ch <- c:
+		default:
+			b.drop(w)
+		}
+	}
+}
+
+// Watch changes to an entity within the tenant of the context, see Watcher
+func (b *Bus) Watch(ctx context.Context, entity string, after string) (<-chan Change, error) {
+	var (
+		seq uint64
+		err error
+		t   = tenant.Get(ctx)
+	)
+
+	if after != "" {
+		if seq, err = strconv.ParseUint(after, 10, 64); err != nil {
+			return nil, ErrInvalidPosition
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if seq > b.seq {
+		return nil, ErrInvalidPosition
+	}
+
+	var missed []Change
+	if after != "" {
+		for _, c := range b.recent {
+			if c.Entity == entity && c.Tenant == t && id(c) > seq {
+				missed = append(missed, c)
+			}
+		}
+	}
+
+	var w = &watcher{entity: entity, tenant: t, ch: make(chan Change, len(missed)+Buffer)}
+	for _, c := range missed {
+		w.ch <- c
+	}
+
+	b.watchers[w] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		b.drop(w)
+		b.mu.Unlock()
+	}()
+
+	return w.ch, nil
+}
+
+// drop a watcher, closing its channel; the lock must be held
+func (b *Bus) drop(w *watcher) {
+	if _, ok := b.watchers[w]; ok {
+		delete(b.watchers, w)
+		close(w.ch)
+	}
+}
+
+// id of a change as a number
+func id(c Change) uint64 {
+	var n, _ = strconv.ParseUint(c.ID, 10, 64)
+	return n
+}
@@ -0,0 +1,166 @@
+package changes
+
+import (
+	"context"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+var (
+	// ErrInvalidPosition is when changes are watched after a position which is not one of the source; a kind of
+	// repository.ErrInvalid
+	ErrInvalidPosition = repository.NewError(repository.ErrInvalid, "invalid position in changes")
+)
+
+func init() {
+	var _ repository.Repositorium = &Repo{}
+	var _ repository.Aggregator = &Repo{}
+	var _ repository.Searcher = &Repo{}
+	var _ repository.Streamer = &Repo{}
+	var _ repository.Counter = &Repo{}
+	var _ Watcher = &Repo{}
+}
+
+// Type of change to an item
+type Type string
+
+const (
+	// Created item
+	Created = Type("created")
+
+	// Updated item; values hold those updated
+	Updated = Type("updated")
+
+	// Deleted item; values hold the id only
+	Deleted = Type("deleted")
+)
+
+// Change to an item of an entity
+type Change struct {
+	// ID is the position of the change in its source, from which watching can be resumed
+	ID     string
+	Type   Type
+	Entity string
+	Tenant string
+	Values *values.Values
+}
+
+// Watcher is a source of changes
+type Watcher interface {
+	// Watch changes to items of an entity, starting after the change of id after or with new changes if after is empty.
+	// The channel is closed once the context is done, or earlier if the watcher cannot keep up, in which case watching
+	// may be resumed after the last change received.
+	Watch(ctx context.Context, entity string, after string) (<-chan Change, error)
+}
+
+// Repo is a repository publishing on a bus the changes made through it by Create, Update and Delete; UpdateWhere and
+// DeleteWhere publish no change. Changes are watched through the bus.
+type Repo struct {
+	repo repository.Repositorium
+	bus  *Bus
+}
+
+// New repository publishing changes made to another on a bus
+func New(repo repository.Repositorium, bus *Bus) *Repo {
+	return &Repo{repo: repo, bus: bus}
+}
+
+// publish a change made in a context
+func (r *Repo) publish(ctx context.Context, t Type, name, id string, vals *values.Values) {
+	if vals == nil {
+		vals = &values.Values{}
+	} else {
+		vals = vals.Clone()
+	}
+
+	vals.Set("id", id)
+
+	r.bus.Publish(Change{Type: t, Entity: name, Tenant: tenant.Get(ctx), Values: vals})
+}
+
+// Watch changes published by the repository, see Bus.Watch
+func (r *Repo) Watch(ctx context.Context, entity string, after string) (<-chan Change, error) {
+	return r.bus.Watch(ctx, entity, after)
+}
+
+// Get a single Name by id
+func (r *Repo) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	return r.repo.Get(ctx, entity, id)
+}
+
+// List multiple Name with pagination rules and conditions
+func (r *Repo) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	return r.repo.List(ctx, entity, p, c...)
+}
+
+// Stream multiple Name with pagination rules and conditions
+func (r *Repo) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	return repository.Stream(ctx, r.repo, entity, p, c...)
+}
+
+// Search items matching terms
+func (r *Repo) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	return repository.Search(ctx, r.repo, entity, terms, p, c...)
+}
+
+// Count Name satisfying conditions
+func (r *Repo) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	return repository.CountOf(ctx, r.repo, entity, c...)
+}
+
+// Aggregate items of an entity
+func (r *Repo) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	if agg, ok := r.repo.(repository.Aggregator); ok {
+		return agg.Aggregate(ctx, entity, a)
+	}
+
+	return nil, repository.ErrNotSupported
+}
+
+// Delete a single Name by id
+func (r *Repo) Delete(ctx context.Context, named repository.Named, id string) error {
+	var err = r.repo.Delete(ctx, named, id)
+	if err == nil {
+		r.publish(ctx, Deleted, named.Name(), id, nil)
+	}
+
+	return err
+}
+
+// DeleteWhere delete multiple Name based on conditions
+func (r *Repo) DeleteWhere(ctx context.Context, named repository.Named, c ...repository.Condition) error {
+	return r.repo.DeleteWhere(ctx, named, c...)
+}
+
+// Create a new Name in persistent storage
+func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	var id, err = r.repo.Create(ctx, named, vals)
+	if err == nil {
+		r.publish(ctx, Created, named.Name(), id, vals)
+	}
+
+	return id, err
+}
+
+// Update an existing Name in persistent storage
+func (r *Repo) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	var err = r.repo.Update(ctx, named, id, vals)
+	if err == nil {
+		r.publish(ctx, Updated, named.Name(), id, vals)
+	}
+
+	return err
+}
+
+// UpdateWhere Values in persistent storage
+func (r *Repo) UpdateWhere(ctx context.Context, named repository.Named, vals *values.Values, c ...repository.Condition) error {
+	return r.repo.UpdateWhere(ctx, named, vals, c...)
+}
+
+// Close connection to the underlying repository
+func (r *Repo) Close() error {
+	return r.repo.Close()
+}
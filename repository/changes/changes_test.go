@@ -0,0 +1,181 @@
+package changes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// stub is a repository whose writes succeed unless the id is "missing"
+type stub struct {
+	repository.Repositorium
+}
+
+func (stub) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	return "1", nil
+}
+
+func (stub) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	if id == "missing" {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+func (stub) Delete(ctx context.Context, named repository.Named, id string) error {
+	return nil
+}
+
+// receive the changes available on a channel
+func receive(ch <-chan Change) []Change {
+	var list []Change
+
+	for {
+		select {
+		case c, ok := <-ch:
+			if !ok {
+				return list
+			}
+
+			list = append(list, c)
+		default:
+			return list
+		}
+	}
+}
+
+func TestRepo(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		bus         = NewBus(10)
+		repo        = New(stub{}, bus)
+		order       = entity.ID("order")
+	)
+
+	defer cancel()
+
+	var ch, err = repo.Watch(ctx, "order", "")
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	repo.Create(ctx, order, values.FromMap(map[string]interface{}{"total": 10}))
+	repo.Update(ctx, order, "missing", values.FromMap(map[string]interface{}{"total": 20}))
+	repo.Update(ctx, order, "1", values.FromMap(map[string]interface{}{"total": 30}))
+	repo.Create(ctx, entity.ID("user"), &values.Values{})
+	repo.Delete(ctx, order, "1")
+
+	var got = receive(ch)
+	if len(got) != 3 {
+		t.Fatalf("received %v, want 3 changes of orders", got)
+	}
+
+	var want = []struct {
+		id    string
+		typ   Type
+		total interface{}
+	}{
+		{"1", Created, 10},
+		{"2", Updated, 30},
+		{"4", Deleted, nil},
+	}
+
+	for i, w := range want {
+		var total interface{}
+		if v := got[i].Values.Get("total"); v != nil {
+			total = v.Value
+		}
+
+		if got[i].ID != w.id || got[i].Type != w.typ || got[i].Values.Get("id").String() != "1" || total != w.total {
+			t.Errorf("change %d = %s %s %v, want %s %s total %v", i, got[i].ID, got[i].Type, got[i].Values, w.id, w.typ, w.total)
+		}
+	}
+
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Errorf("channel open after context done")
+	}
+}
+
+func TestBus_Watch(t *testing.T) {
+	var (
+		bus = NewBus(3)
+		acm = tenant.Set(context.Background(), "acme")
+	)
+
+	for i := 0; i < 4; i++ {
+		bus.Publish(Change{Entity: "order", Tenant: "acme"})
+		bus.Publish(Change{Entity: "order", Tenant: "other"})
+	}
+
+	var ids = func(list []Change) []string {
+		var s []string
+		for _, c := range list {
+			s = append(s, c.ID)
+		}
+
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		after string
+		want  []string
+		err   error
+	}{
+		{name: "New changes only", after: "", want: nil},
+		{name: "Resumed", after: "5", want: []string{"7"}},
+		{name: "Resumed after changes no longer kept", after: "1", want: []string{"7"}},
+		{name: "Latest", after: "8", want: nil},
+		{name: "Unknown", after: "9", err: ErrInvalidPosition},
+		{name: "Invalid", after: "x", err: ErrInvalidPosition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctx, cancel = context.WithCancel(acm)
+			defer cancel()
+
+			var ch, err = bus.Watch(ctx, "order", tt.after)
+			if err != tt.err {
+				t.Fatalf("Watch() error = %v, want %v", err, tt.err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := ids(receive(ch)); len(got) != len(tt.want) || (len(got) != 0 && got[0] != tt.want[0]) {
+				t.Errorf("Watch() replayed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBus_slowWatcher(t *testing.T) {
+	var (
+		bus         = NewBus(0)
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	defer cancel()
+
+	var ch, _ = bus.Watch(ctx, "order", "")
+	for i := 0; i <= Buffer; i++ {
+		bus.Publish(Change{Entity: "order"})
+	}
+
+	var got = receive(ch)
+	if len(got) != Buffer {
+		t.Errorf("received %d changes, want %d", len(got), Buffer)
+	}
+
+	if _, ok := <-ch; ok {
+		t.Errorf("channel of watcher falling behind is open")
+	}
+}
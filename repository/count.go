@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+// Counter is a repository able to count records without fetching them
+type Counter interface {
+	// Count records satisfying conditions
+	Count(ctx context.Context, entity entity.Entity, c ...Condition) (int64, error)
+}
+
+// CountOf records of an entity satisfying conditions; if the repository is not a Counter, records are listed then
+// counted
+func CountOf(ctx context.Context, repo Repositorium, e entity.Entity, c ...Condition) (int64, error) {
+	if counter, ok := repo.(Counter); ok {
+		return counter.Count(ctx, e, c...)
+	}
+
+	var list, err = repo.List(ctx, e, Pagination{}, c...)
+
+	return int64(len(list)), err
+}
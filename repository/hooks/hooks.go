@@ -0,0 +1,276 @@
+package hooks
+
+import (
+	"context"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ repository.Repositorium = &Repo{}
+	var _ repository.Aggregator = &Repo{}
+	var _ repository.Searcher = &Repo{}
+	var _ repository.Streamer = &Repo{}
+	var _ repository.Counter = &Repo{}
+}
+
+// Op is a repository operation hooks apply to
+type Op uint8
+
+const (
+	// Get a single item by id
+	Get = Op(iota)
+
+	// List items satisfying conditions; also applies to Stream, Search, Count and Aggregate
+	List
+
+	// Create an item
+	Create
+
+	// Update an item by id
+	Update
+
+	// UpdateWhere updates items satisfying conditions
+	UpdateWhere
+
+	// Delete an item by id
+	Delete
+
+	// DeleteWhere deletes items satisfying conditions
+	DeleteWhere
+)
+
+// Event describes an operation to hooks. Before hooks may modify Values and Conditions to change the operation; after
+// hooks see the outcome in ID, Values (Get), Results (List) and Err.
+type Event struct {
+	Op     Op
+	Entity string
+
+	// ID of the item for Get, Update and Delete; of the item created after Create
+	ID string
+
+	// Values to create or update; the item found after Get
+	Values *values.Values
+
+	// Conditions of List, UpdateWhere and DeleteWhere
+	Conditions []repository.Condition
+
+	// Results of List, after
+	Results []values.Values
+
+	// Err returned by the operation, after
+	Err error
+}
+
+// Before is a hook run before an operation; an error vetoes the operation and is returned to the caller
+type Before func(ctx context.Context, e *Event) error
+
+// After is a hook run after an operation which was not vetoed, whether it failed or not
+type After func(ctx context.Context, e *Event)
+
+// key of hooks; an empty entity applies to all entities
+type key struct {
+	op     Op
+	entity string
+}
+
+// Repo is a repository running hooks around the operations of another. Hooks for all entities run before those of the
+// entity, each in the order they were added. Hooks must be added before the repository is used.
+type Repo struct {
+	repo   repository.Repositorium
+	before map[key][]Before
+	after  map[key][]After
+}
+
+// New repository running hooks around operations of another
+func New(repo repository.Repositorium) *Repo {
+	return &Repo{
+		repo:   repo,
+		before: make(map[key][]Before),
+		after:  make(map[key][]After),
+	}
+}
+
+// Before adds a hook run before an operation on an entity, or on all entities if entity is empty
+func (r *Repo) Before(op Op, entity string, h Before) *Repo {
+	var k = key{op: op, entity: entity}
+	r.before[k] = append(r.before[k], h)
+	return r
+}
+
+// After adds a hook run after an operation on an entity, or on all entities if entity is empty
+func (r *Repo) After(op Op, entity string, h After) *Repo {
+	var k = key{op: op, entity: entity}
+	r.after[k] = append(r.after[k], h)
+	return r
+}
+
+// runBefore runs hooks before an operation, stopping at the first error
+func (r *Repo) runBefore(ctx context.Context, e *Event) error {
+	for _, k := range []key{{op: e.Op}, {op: e.Op, entity: e.Entity}} {
+		for _, h := range r.before[k] {
+			if err := h(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// runAfter runs hooks after an operation
+func (r *Repo) runAfter(ctx context.Context, e *Event) {
+	for _, k := range []key{{op: e.Op}, {op: e.Op, entity: e.Entity}} {
+		for _, h := range r.after[k] {
+			h(ctx, e)
+		}
+	}
+}
+
+// Get a single Name by id
+func (r *Repo) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	var e = Event{Op: Get, Entity: entity.Name(), ID: id}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return nil, err
+	}
+
+	e.Values, e.Err = r.repo.Get(ctx, entity, e.ID)
+	r.runAfter(ctx, &e)
+
+	return e.Values, e.Err
+}
+
+// List multiple Name with pagination rules and conditions
+func (r *Repo) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var e = Event{Op: List, Entity: entity.Name(), Conditions: c}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return nil, err
+	}
+
+	e.Results, e.Err = r.repo.List(ctx, entity, p, e.Conditions...)
+	r.runAfter(ctx, &e)
+
+	return e.Results, e.Err
+}
+
+// Stream multiple Name with pagination rules and conditions; only before List hooks apply
+func (r *Repo) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	var e = Event{Op: List, Entity: entity.Name(), Conditions: c}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return nil, err
+	}
+
+	return repository.Stream(ctx, r.repo, entity, p, e.Conditions...)
+}
+
+// Search items matching terms; List hooks apply
+func (r *Repo) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var e = Event{Op: List, Entity: entity.Name(), Conditions: c}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return nil, err
+	}
+
+	e.Results, e.Err = repository.Search(ctx, r.repo, entity, terms, p, e.Conditions...)
+	r.runAfter(ctx, &e)
+
+	return e.Results, e.Err
+}
+
+// Count Name satisfying conditions; only before List hooks apply
+func (r *Repo) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	var e = Event{Op: List, Entity: entity.Name(), Conditions: c}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return 0, err
+	}
+
+	return repository.CountOf(ctx, r.repo, entity, e.Conditions...)
+}
+
+// Aggregate items of an entity; only before List hooks apply, to the conditions of the aggregation
+func (r *Repo) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	var agg, ok = r.repo.(repository.Aggregator)
+	if !ok {
+		return nil, repository.ErrNotSupported
+	}
+
+	var e = Event{Op: List, Entity: entity.Name(), Conditions: a.Conditions}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return nil, err
+	}
+
+	a.Conditions = e.Conditions
+
+	return agg.Aggregate(ctx, entity, a)
+}
+
+// Delete a single Name by id
+func (r *Repo) Delete(ctx context.Context, named repository.Named, id string) error {
+	var e = Event{Op: Delete, Entity: named.Name(), ID: id}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return err
+	}
+
+	e.Err = r.repo.Delete(ctx, named, e.ID)
+	r.runAfter(ctx, &e)
+
+	return e.Err
+}
+
+// DeleteWhere delete multiple Name based on conditions
+func (r *Repo) DeleteWhere(ctx context.Context, named repository.Named, c ...repository.Condition) error {
+	var e = Event{Op: DeleteWhere, Entity: named.Name(), Conditions: c}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return err
+	}
+
+	e.Err = r.repo.DeleteWhere(ctx, named, e.Conditions...)
+	r.runAfter(ctx, &e)
+
+	return e.Err
+}
+
+// Create a new Name in persistent storage
+func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	var e = Event{Op: Create, Entity: named.Name(), Values: vals}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return "", err
+	}
+
+	e.ID, e.Err = r.repo.Create(ctx, named, e.Values)
+	r.runAfter(ctx, &e)
+
+	return e.ID, e.Err
+}
+
+// Update an existing Name in persistent storage
+func (r *Repo) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	var e = Event{Op: Update, Entity: named.Name(), ID: id, Values: vals}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return err
+	}
+
+	e.Err = r.repo.Update(ctx, named, e.ID, e.Values)
+	r.runAfter(ctx, &e)
+
+	return e.Err
+}
+
+// UpdateWhere Values in persistent storage
+func (r *Repo) UpdateWhere(ctx context.Context, named repository.Named, vals *values.Values, c ...repository.Condition) error {
+	var e = Event{Op: UpdateWhere, Entity: named.Name(), Values: vals, Conditions: c}
+	if err := r.runBefore(ctx, &e); err != nil {
+		return err
+	}
+
+	e.Err = r.repo.UpdateWhere(ctx, named, e.Values, e.Conditions...)
+	r.runAfter(ctx, &e)
+
+	return e.Err
+}
+
+// Close connection to the underlying repository
+func (r *Repo) Close() error {
+	return r.repo.Close()
+}
@@ -0,0 +1,129 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// recorder is a repository recording the arguments it receives
+type recorder struct {
+	repository.Repositorium
+	created *values.Values
+	conds   []repository.Condition
+	deleted []string
+	fail    error
+}
+
+func (r *recorder) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	r.conds = c
+	return []values.Values{{}}, nil
+}
+
+func (r *recorder) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	r.conds = a.Conditions
+	return nil, nil
+}
+
+func (r *recorder) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	r.created = vals
+	return "1", nil
+}
+
+func (r *recorder) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	return r.fail
+}
+
+func (r *recorder) Delete(ctx context.Context, named repository.Named, id string) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func TestRepo(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		backend    = &recorder{}
+		r          = New(backend)
+		user       = entity.ID("user")
+		order      = entity.ID("order")
+		referenced = errors.New("user has orders")
+		ran        []string
+		updates    []error
+	)
+
+	r.Before(Create, "user", func(ctx context.Context, e *Event) error {
+		if v := e.Values.Get("email"); v != nil {
+			e.Values.Set("email", strings.ToLower(v.String()))
+		}
+
+		ran = append(ran, "user")
+		return nil
+	}).Before(Create, "", func(ctx context.Context, e *Event) error {
+		ran = append(ran, "all")
+		return nil
+	}).Before(Delete, "user", func(ctx context.Context, e *Event) error {
+		if e.ID == "referenced" {
+			return referenced
+		}
+
+		return nil
+	}).Before(List, "", func(ctx context.Context, e *Event) error {
+		e.Conditions = append(e.Conditions, repository.Condition{Attribute: "deleted", Value: false})
+		return nil
+	}).After(Update, "", func(ctx context.Context, e *Event) {
+		updates = append(updates, e.Err)
+	}).After(Create, "user", func(ctx context.Context, e *Event) {
+		if e.ID != "1" {
+			t.Errorf("after Create ID = %q, want 1", e.ID)
+		}
+	})
+
+	r.Create(ctx, user, values.FromMap(map[string]interface{}{"email": "Jane@Example.COM"}))
+	if email := backend.created.Get("email").String(); email != "jane@example.com" {
+		t.Errorf("created email = %q, want normalised", email)
+	}
+
+	if strings.Join(ran, ",") != "all,user" {
+		t.Errorf("hooks ran in order %v, want all then user", ran)
+	}
+
+	r.Create(ctx, order, values.FromMap(map[string]interface{}{"email": "Jane@Example.COM"}))
+	if email := backend.created.Get("email").String(); email != "Jane@Example.COM" {
+		t.Errorf("hook of user applied to order: %q", email)
+	}
+
+	if err := r.Delete(ctx, user, "referenced"); err != referenced {
+		t.Errorf("Delete() error = %v, want veto %v", err, referenced)
+	}
+
+	r.Delete(ctx, user, "free")
+	r.Delete(ctx, order, "referenced")
+	if strings.Join(backend.deleted, ",") != "free,referenced" {
+		t.Errorf("deleted %v, want free and order referenced", backend.deleted)
+	}
+
+	r.List(ctx, user, repository.Pagination{}, repository.Condition{Attribute: "name", Value: "jane"})
+	r.Stream(ctx, user, repository.Pagination{})
+	if len(backend.conds) != 1 || backend.conds[0].Attribute != "deleted" {
+		t.Errorf("Stream() conditions = %v, want condition added by hook", backend.conds)
+	}
+
+	r.Aggregate(ctx, user, repository.Aggregation{Aggregates: []repository.Aggregate{{Func: repository.Count}}})
+	if len(backend.conds) != 1 || backend.conds[0].Attribute != "deleted" {
+		t.Errorf("Aggregate() conditions = %v, want condition added by hook", backend.conds)
+	}
+
+	backend.fail = repository.ErrNotFound
+	r.Update(ctx, order, "1", &values.Values{})
+	backend.fail = nil
+	r.Update(ctx, user, "1", &values.Values{})
+
+	if len(updates) != 2 || updates[0] != repository.ErrNotFound || updates[1] != nil {
+		t.Errorf("after Update observed %v", updates)
+	}
+}
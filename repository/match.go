@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fluxynet/gocipe/values"
+)
+
+// Match checks in process whether values satisfy all conditions, as a repository would. Conditions on attributes absent
+// from the values are not evaluated, so that partial values (example those of an update) are matched on what they hold.
+func Match(vals *values.Values, c ...Condition) bool {
+	for i := range c {
+		var v, ok = lookup(vals, c[i].Attribute)
+		if ok && !matches(v, c[i].Operator, c[i].Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lookup the value of an attribute, which may be a path within a json field
+func lookup(vals *values.Values, attr string) (interface{}, bool) {
+	var name, path = SplitPath(attr)
+
+	var v = vals.Get(name)
+	if v == nil {
+		return nil, false
+	}
+
+	var x = v.Value
+	for _, seg := range path {
+		var m, ok = x.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if x, ok = m[seg]; !ok {
+			return nil, false
+		}
+	}
+
+	return deref(x), true
+}
+
+// matches checks a value against a single condition
+func matches(v interface{}, op ConditionOperator, want interface{}) bool {
+	want = deref(want)
+
+	switch op {
+	case Equals:
+		return compare(v, want) == 0
+	case NotEquals:
+		return compare(v, want) != 0
+	case GreaterThan:
+		return compare(v, want) == 1
+	case GreaterOrEqualTo:
+		var n = compare(v, want)
+		return n == 0 || n == 1
+	case LessThan:
+		return compare(v, want) == -1
+	case LessOrEqualTo:
+		var n = compare(v, want)
+		return n == 0 || n == -1
+	case In, NotIn:
+		var found bool
+		if list, ok := want.([]interface{}); ok {
+			for i := range list {
+				if compare(v, deref(list[i])) == 0 {
+					found = true
+					break
+				}
+			}
+		}
+
+		return found == (op == In)
+	}
+
+	var s, pattern = fmt.Sprint(v), fmt.Sprint(want)
+	if op.IgnoresCase() {
+		s, pattern = strings.ToLower(s), strings.ToLower(pattern)
+	}
+
+	switch op {
+	case Like:
+		return like(s, pattern)
+	case Contains, IContains:
+		return strings.Contains(s, pattern)
+	case StartsWith, IStartsWith:
+		return strings.HasPrefix(s, pattern)
+	case EndsWith, IEndsWith:
+		return strings.HasSuffix(s, pattern)
+	}
+
+	return false
+}
+
+// compare two values: -1, 0 or 1 if a is less than, equal to or greater than b; 2 if they cannot be compared. Numbers
+// are compared whatever their type; values which are not numbers nor strings can only be equal.
+func compare(a, b interface{}) int {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+
+			return 0
+		}
+	}
+
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	}
+
+	if fmt.Sprint(a) == fmt.Sprint(b) {
+		return 0
+	}
+
+	return 2
+}
+
+// number converts numeric values to float64
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+
+	return 0, false
+}
+
+// deref pointers to values as found in values read from repositories
+func deref(v interface{}) interface{} {
+	switch p := v.(type) {
+	case *string:
+		if p != nil {
+			return *p
+		}
+	case *int64:
+		if p != nil {
+			return *p
+		}
+	case *float64:
+		if p != nil {
+			return *p
+		}
+	case *bool:
+		if p != nil {
+			return *p
+		}
+	default:
+		return v
+	}
+
+	return nil
+}
+
+// like matches a string against a LIKE pattern: % matches any sequence of characters, _ any single character and \
+// escapes the next character
+func like(s, pattern string) bool {
+	var (
+		str = []rune(s)
+		pat = []rune(pattern)
+	)
+
+	for len(pat) != 0 {
+		switch pat[0] {
+		case '%':
+			for i := 0; i <= len(str); i++ {
+				if like(string(str[i:]), string(pat[1:])) {
+					return true
+				}
+			}
+
+			return false
+		case '_':
+			if len(str) == 0 {
+				return false
+			}
+		case '\\':
+			if len(pat) > 1 {
+				pat = pat[1:]
+			}
+
+			fallthrough
+		default:
+			if len(str) == 0 || str[0] != pat[0] {
+				return false
+			}
+		}
+
+		str, pat = str[1:], pat[1:]
+	}
+
+	return len(str) == 0
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/fluxynet/gocipe/values"
+)
+
+func TestMatch(t *testing.T) {
+	var (
+		name = "Mauritius"
+		vals = values.FromMap(map[string]interface{}{
+			"name":       &name,
+			"population": int64(1265000),
+			"area":       2040.0,
+			"island":     true,
+			"attrs":      map[string]interface{}{"currency": "MUR"},
+		})
+	)
+
+	tests := []struct {
+		name string
+		c    []Condition
+		want bool
+	}{
+		{name: "No conditions", want: true},
+		{name: "Equals", c: []Condition{{Attribute: "name", Value: "Mauritius"}}, want: true},
+		{name: "Not equals", c: []Condition{{Attribute: "name", Operator: NotEquals, Value: "Mauritius"}}, want: false},
+		{name: "Numbers of different types", c: []Condition{{Attribute: "population", Operator: GreaterThan, Value: 1e6}}, want: true},
+		{name: "Less or equal", c: []Condition{{Attribute: "area", Operator: LessOrEqualTo, Value: int64(2040)}}, want: true},
+		{name: "Bool", c: []Condition{{Attribute: "island", Value: false}}, want: false},
+		{name: "All conditions", c: []Condition{{Attribute: "island", Value: true}, {Attribute: "area", Operator: LessThan, Value: 100}}, want: false},
+		{name: "In", c: []Condition{{Attribute: "name", Operator: In, Value: []interface{}{"Fiji", "Mauritius"}}}, want: true},
+		{name: "Not in", c: []Condition{{Attribute: "name", Operator: NotIn, Value: []interface{}{"Fiji", "Mauritius"}}}, want: false},
+		{name: "Like", c: []Condition{{Attribute: "name", Operator: Like, Value: "Ma_r%s"}}, want: true},
+		{name: "Like anchored", c: []Condition{{Attribute: "name", Operator: Like, Value: "aur%"}}, want: false},
+		{name: "Like escaped", c: []Condition{{Attribute: "name", Operator: Like, Value: `Mauritiu\_`}}, want: false},
+		{name: "Contains", c: []Condition{{Attribute: "name", Operator: Contains, Value: "rit"}}, want: true},
+		{name: "Case", c: []Condition{{Attribute: "name", Operator: StartsWith, Value: "maur"}}, want: false},
+		{name: "Ignoring case", c: []Condition{{Attribute: "name", Operator: IStartsWith, Value: "maur"}}, want: true},
+		{name: "Json path", c: []Condition{{Attribute: "attrs.currency", Operator: IEndsWith, Value: "ur"}}, want: true},
+		{name: "Absent attribute", c: []Condition{{Attribute: "capital", Value: "Port Louis"}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(vals, tt.c...); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ repository.Aggregator = &Repo{}
+}
+
+// fieldPath returns the aggregation expression referring to an attribute, example $price
+func fieldPath(attr string) string {
+	if attr == "id" {
+		return "$_id"
+	}
+
+	return "$" + attr
+}
+
+// Accumulator returns the $group accumulator of an aggregate
+func Accumulator(a repository.Aggregate) bson.M {
+	switch a.Func {
+	case repository.Count:
+		if a.Attribute == "" {
+			return bson.M{"$sum": 1}
+		}
+
+		// null and missing values are lower than any other value
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{fieldPath(a.Attribute), nil}}, 1, 0}}}
+	case repository.Sum:
+		return bson.M{"$sum": fieldPath(a.Attribute)}
+	case repository.Avg:
+		return bson.M{"$avg": fieldPath(a.Attribute)}
+	case repository.Min:
+		return bson.M{"$min": fieldPath(a.Attribute)}
+	case repository.Max:
+		return bson.M{"$max": fieldPath(a.Attribute)}
+	}
+
+	return nil
+}
+
+// AggregationToPipeline returns the mongo aggregation pipeline of an aggregation; group-by attributes are projected
+// back to the top level so that having and ordering may refer to them
+func AggregationToPipeline(a repository.Aggregation) (mongo.Pipeline, error) {
+	var (
+		pipeline mongo.Pipeline
+		id       interface{}
+		group    = bson.D{}
+		project  = bson.D{{Key: "_id", Value: 0}}
+	)
+
+	if len(a.Conditions) != 0 {
+		var filters, err = ConditionsToBsonD(a.Conditions)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: filters}})
+	}
+
+	if len(a.GroupBy) != 0 {
+		var keys = make(bson.D, len(a.GroupBy))
+		for i, attr := range a.GroupBy {
+			keys[i] = bson.E{Key: attr, Value: fieldPath(attr)}
+			project = append(project, bson.E{Key: attr, Value: "$_id." + attr})
+		}
+
+		id = keys
+	}
+
+	group = append(group, bson.E{Key: "_id", Value: id})
+
+	for _, agg := range a.Aggregates {
+		group = append(group, bson.E{Key: agg.Name(), Value: Accumulator(agg)})
+		project = append(project, bson.E{Key: agg.Name(), Value: 1})
+	}
+
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: group}},
+		bson.D{{Key: "$project", Value: project}},
+	)
+
+	if len(a.Having) != 0 {
+		var having, err = ConditionsToBsonD(a.Having)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: having}})
+	}
+
+	if len(a.Order) != 0 {
+		var sort = make(bson.D, len(a.Order))
+		for i, o := range a.Order {
+			sort[i] = bson.E{Key: o.Attribute, Value: 1}
+			if o.Sort == repository.Descending {
+				sort[i].Value = -1
+			}
+		}
+
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+	}
+
+	if a.Limit != 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: a.Limit}})
+	}
+
+	return pipeline, nil
+}
+
+// Aggregate items of an entity grouped by attributes
+func (r *Repo) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	var (
+		data   []values.Values
+		cursor *mongo.Cursor
+	)
+
+	var pipeline, err = AggregationToPipeline(a)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err = r.db.Collection(entity.Name()).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var datum bson.M
+		if err = cursor.Decode(&datum); err != nil {
+			return nil, err
+		}
+
+		var vals values.Values
+		for _, attr := range a.GroupBy {
+			vals.Set(attr, datum[attr])
+		}
+
+		for _, agg := range a.Aggregates {
+			vals.Set(agg.Name(), datum[agg.Name()])
+		}
+
+		data = append(data, vals)
+	}
+
+	return data, cursor.Err()
+}
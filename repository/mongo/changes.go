@@ -0,0 +1,114 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/fluxynet/gocipe/repository/changes"
+	"github.com/fluxynet/gocipe/repository/tenancy"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ changes.Watcher = &Repo{}
+}
+
+// changeEvent is a document of a change stream
+type changeEvent struct {
+	ID struct {
+		Data string `bson:"_data"`
+	} `bson:"_id"`
+	OperationType string `bson:"operationType"`
+	DocumentKey   bson.M `bson:"documentKey"`
+	FullDocument  bson.M `bson:"fullDocument"`
+	Update        struct {
+		UpdatedFields bson.M `bson:"updatedFields"`
+	} `bson:"updateDescription"`
+}
+
+// ChangeStreamPipeline filters change streams to changes of documents, of a tenant if not empty (see tenancy.Attribute).
+// Deleted documents can not be filtered by tenant since change streams only carry their key.
+func ChangeStreamPipeline(tenant string) mongo.Pipeline {
+	var match = bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}}}
+
+	if tenant != "" {
+		match = bson.M{"$or": bson.A{
+			bson.M{
+				"operationType":                     bson.M{"$in": bson.A{"insert", "update", "replace"}},
+				"fullDocument." + tenancy.Attribute: tenant,
+			},
+			bson.M{"operationType": "delete"},
+		}}
+	}
+
+	return mongo.Pipeline{{{Key: "$match", Value: match}}}
+}
+
+// Watch changes to a collection through a change stream (requires a replica set); change ids are resume tokens.
+// Updated and replaced documents are reported as updated with all their values, as looked up when the change is read.
+// When the context has a tenant, only changes of its documents are reported, except deletions which are reported for
+// all tenants with the id of the document only.
+func (r *Repo) Watch(ctx context.Context, entity string, after string) (<-chan changes.Change, error) {
+	var (
+		t    = tenant.Get(ctx)
+		opts = options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	)
+
+	if after != "" {
+		opts.SetResumeAfter(bson.M{"_data": after})
+	}
+
+	var stream, err = r.db.Collection(entity).Watch(ctx, ChangeStreamPipeline(t), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var ch = make(chan changes.Change, changes.Buffer)
+
+	go func() {
+		defer close(ch)
+		defer stream.Close(context.Background())
+
+		for stream.Next(ctx) {
+			var e changeEvent
+			if stream.Decode(&e) != nil {
+				return
+			}
+
+			var c = changes.Change{ID: e.ID.Data, Entity: entity, Tenant: t, Values: &values.Values{}}
+
+			switch e.OperationType {
+			case "insert":
+				c.Type = changes.Created
+				c.Values.FromMap(e.FullDocument)
+			case "replace":
+				c.Type = changes.Updated
+				c.Values.FromMap(e.FullDocument)
+			case "update":
+				c.Type = changes.Updated
+				if e.FullDocument != nil {
+					c.Values.FromMap(e.FullDocument)
+				} else { // deleted before it could be looked up
+					c.Values.FromMap(e.Update.UpdatedFields)
+				}
+			case "delete":
+				c.Type = changes.Deleted
+			}
+
+			c.Values.Set("_id", e.DocumentKey["_id"])
+			fromBsonID(c.Values)
+
+			select {
+			case ch <- c:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
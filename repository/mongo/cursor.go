@@ -0,0 +1,108 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ repository.Streamer = &Repo{}
+}
+
+// Cursor decodes documents from a mongo cursor one at a time
+type Cursor struct {
+	ctx    context.Context
+	cursor *mongo.Cursor
+	fields fields.Fields
+	vals   values.Values
+	err    error
+}
+
+// Next decodes the next document
+func (c *Cursor) Next() bool {
+	if c.err != nil || !c.cursor.Next(c.ctx) {
+		return false
+	}
+
+	var datum = c.fields.GetEmptyValues()
+	if c.err = c.cursor.Decode(datum); c.err != nil {
+		return false
+	}
+
+	c.vals = values.Values{}
+	c.vals.FromMap(datum)
+	c.vals.Unset(scoreField) // present on search results
+	fromBsonID(&c.vals)
+
+	return true
+}
+
+// Value of the current document
+func (c *Cursor) Value() values.Values {
+	return c.vals
+}
+
+// Err returns the error which stopped iteration, if any
+func (c *Cursor) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+
+	return c.cursor.Err()
+}
+
+// Close the cursor
+func (c *Cursor) Close() error {
+	return c.cursor.Close(c.ctx)
+}
+
+// FindOptions returns find options applying pagination: sort, skip and limit
+func FindOptions(p repository.Pagination) *options.FindOptions {
+	var opts = options.Find()
+
+	if len(p.Order) != 0 {
+		var sort = make(bson.D, len(p.Order))
+		for i, o := range p.Order {
+			sort[i] = bson.E{Key: o.Attribute, Value: 1}
+			if o.Sort == repository.Descending {
+				sort[i].Value = -1
+			}
+		}
+
+		opts.SetSort(sort)
+	}
+
+	if p.Offset != 0 {
+		opts.SetSkip(int64(p.Offset))
+	}
+
+	if p.Limit != 0 {
+		opts.SetLimit(int64(p.Limit))
+	}
+
+	return opts
+}
+
+// Stream multiple Name with pagination rules and conditions, one document at a time
+func (r *Repo) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	var filters, err = ConditionsToBsonD(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor *mongo.Cursor
+	cursor, err = r.db.Collection(entity.Name()).Find(ctx, filters, FindOptions(p).SetProjection(Projection(entity.Fields())))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor{ctx: ctx, cursor: cursor, fields: entity.Fields()}, nil
+}
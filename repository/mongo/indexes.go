@@ -0,0 +1,179 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+// indexInfo is an index as reported by listIndexes
+type indexInfo struct {
+	Name               string `bson:"name"`
+	Key                bson.D `bson:"key"`
+	Unique             bool   `bson:"unique"`
+	ExpireAfterSeconds *int32 `bson:"expireAfterSeconds"`
+	Weights            bson.M `bson:"weights"`
+}
+
+// matches checks if an existing index corresponds to a declaration
+func (x indexInfo) matches(i entity.Index) bool {
+	if x.Unique != i.Unique {
+		return false
+	}
+
+	var expire int32
+	if x.ExpireAfterSeconds != nil {
+		expire = *x.ExpireAfterSeconds
+	}
+
+	if expire != int32(i.ExpireAfter/time.Second) {
+		return false
+	}
+
+	if i.Text { // text indexes are reported with internal keys, fields are in weights
+		if len(x.Weights) != len(i.Fields) {
+			return false
+		}
+
+		for _, c := range i.Columns() {
+			if _, ok := x.Weights[c.Name]; !ok {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	var keys = IndexKeys(i)
+	if len(keys) != len(x.Key) {
+		return false
+	}
+
+	for j := range keys {
+		if keys[j].Key != x.Key[j].Key || keys[j].Value != toInt(x.Key[j].Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func toInt(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int32:
+		return int(x)
+	case int64:
+		return int(x)
+	case float64:
+		return int(x)
+	}
+
+	return v
+}
+
+// EnsureIndexes creates indexes declared by entities, recreating those whose definition has changed. It is idempotent
+// and leaves indexes which are not declared untouched.
+func (r *Repo) EnsureIndexes(ctx context.Context, entities entity.Entities) error {
+	for _, e := range entities {
+		var idx = entity.IndexesOf(e)
+		if len(idx) == 0 {
+			continue
+		}
+
+		var (
+			view          = r.db.Collection(e.Name()).Indexes()
+			existing, err = listIndexes(ctx, view)
+		)
+
+		if err != nil {
+			return err
+		}
+
+		for _, i := range idx {
+			var name = i.IndexName()
+
+			if x, ok := existing[name]; ok && x.matches(i) {
+				continue
+			} else if ok {
+				if _, err = view.DropOne(ctx, name); err != nil {
+					return err
+				}
+			}
+
+			if _, err = view.CreateOne(ctx, IndexModel(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// listIndexes of a collection by name
+func listIndexes(ctx context.Context, view mongo.IndexView) (map[string]indexInfo, error) {
+	var cursor, err = view.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var existing = make(map[string]indexInfo)
+	for cursor.Next(ctx) {
+		var x indexInfo
+		if err = cursor.Decode(&x); err != nil {
+			return nil, err
+		}
+
+		existing[x.Name] = x
+	}
+
+	return existing, cursor.Err()
+}
+
+// isDuplicateKey checks if an error is caused by a unique index violation
+func isDuplicateKey(err error) bool {
+	var (
+		we mongo.WriteException
+		be mongo.BulkWriteException
+		ce mongo.CommandError
+	)
+
+	switch {
+	case errors.As(err, &we):
+		for _, e := range we.WriteErrors {
+			if isDuplicateKeyCode(e.Code) {
+				return true
+			}
+		}
+	case errors.As(err, &be):
+		for _, e := range be.WriteErrors {
+			if isDuplicateKeyCode(e.Code) {
+				return true
+			}
+		}
+	case errors.As(err, &ce):
+		return isDuplicateKeyCode(int(ce.Code))
+	}
+
+	return false
+}
+
+func isDuplicateKeyCode(code int) bool {
+	return code == 11000 || code == 11001 || code == 12582
+}
+
+// conflict maps duplicate key errors to repository.ErrConflict
+func conflict(err error) error {
+	if isDuplicateKey(err) {
+		return repository.ErrConflict
+	}
+
+	return err
+}
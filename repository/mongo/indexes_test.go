@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+func expire(s int32) *int32 {
+	return &s
+}
+
+func TestIndexInfo_matches(t *testing.T) {
+	tests := []struct {
+		name  string
+		info  indexInfo
+		index entity.Index
+		want  bool
+	}{
+		{
+			name:  "Same keys",
+			info:  indexInfo{Key: bson.D{{Key: "a", Value: int32(1)}, {Key: "b", Value: int32(-1)}}},
+			index: entity.Index{Fields: []string{"a", "-b"}},
+			want:  true,
+		},
+		{
+			name:  "Different direction",
+			info:  indexInfo{Key: bson.D{{Key: "a", Value: int32(1)}}},
+			index: entity.Index{Fields: []string{"-a"}},
+			want:  false,
+		},
+		{
+			name:  "Different order",
+			info:  indexInfo{Key: bson.D{{Key: "b", Value: int32(1)}, {Key: "a", Value: int32(1)}}},
+			index: entity.Index{Fields: []string{"a", "b"}},
+			want:  false,
+		},
+		{
+			name:  "Unique changed",
+			info:  indexInfo{Key: bson.D{{Key: "a", Value: int32(1)}}},
+			index: entity.Index{Fields: []string{"a"}, Unique: true},
+			want:  false,
+		},
+		{
+			name:  "Same expiry",
+			info:  indexInfo{Key: bson.D{{Key: "at", Value: float64(1)}}, ExpireAfterSeconds: expire(3600)},
+			index: entity.Index{Fields: []string{"at"}, ExpireAfter: time.Hour},
+			want:  true,
+		},
+		{
+			name:  "Expiry changed",
+			info:  indexInfo{Key: bson.D{{Key: "at", Value: int32(1)}}, ExpireAfterSeconds: expire(60)},
+			index: entity.Index{Fields: []string{"at"}, ExpireAfter: time.Hour},
+			want:  false,
+		},
+		{
+			name: "Same text",
+			info: indexInfo{
+				Key:     bson.D{{Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}},
+				Weights: bson.M{"title": int32(1), "body": int32(1)},
+			},
+			index: entity.Index{Fields: []string{"title", "body"}, Text: true},
+			want:  true,
+		},
+		{
+			name: "Text fields changed",
+			info: indexInfo{
+				Key:     bson.D{{Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}},
+				Weights: bson.M{"title": int32(1)},
+			},
+			index: entity.Index{Fields: []string{"title", "body"}, Text: true},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.matches(tt.index); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
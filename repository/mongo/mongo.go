@@ -1,9 +1,15 @@
 package mongo
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields"
 	"github.com/fluxynet/gocipe/values"
 )
 
@@ -26,6 +32,10 @@ func ConditionsToBsonD(c []repository.Condition) (bson.D, error) {
 			return nil, repository.ErrInvalidAttribute
 		}
 
+		if name == "id" {
+			name, val = "_id", ObjectIDs(val)
+		}
+
 		switch c[i].Operator {
 		default:
 			return nil, repository.ErrInvalidConditionOperator
@@ -59,10 +69,11 @@ func ConditionsToBsonD(c []repository.Condition) (bson.D, error) {
 				Key:   name,
 				Value: bson.M{"$lte": val},
 			}
-		case repository.Like:
+		case repository.Like, repository.Contains, repository.StartsWith, repository.EndsWith,
+			repository.IContains, repository.IStartsWith, repository.IEndsWith:
 			filters[i] = bson.E{
 				Key:   name,
-				Value: bson.M{"$regex": val},
+				Value: Regex(c[i].Operator, val),
 			}
 		case repository.In:
 			filters[i] = bson.E{
@@ -80,6 +91,141 @@ func ConditionsToBsonD(c []repository.Condition) (bson.D, error) {
 	return filters, nil
 }
 
+// Regex returns the $regex filter of a pattern condition; values are escaped so that they are matched literally, except
+// for Like whose % and _ wildcards are translated
+func Regex(op repository.ConditionOperator, v interface{}) bson.M {
+	var s, pattern string
+
+	switch x := v.(type) {
+	case string:
+		s = x
+	case *string:
+		if x != nil {
+			s = *x
+		}
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	switch op {
+	case repository.Like:
+		return bson.M{"$regex": LikeToRegex(s), "$options": "s"}
+	case repository.Contains, repository.IContains:
+		pattern = regexp.QuoteMeta(s)
+	case repository.StartsWith, repository.IStartsWith:
+		pattern = "^" + regexp.QuoteMeta(s)
+	case repository.EndsWith, repository.IEndsWith:
+		pattern = regexp.QuoteMeta(s) + "$"
+	}
+
+	if op.IgnoresCase() {
+		return bson.M{"$regex": pattern, "$options": "i"}
+	}
+
+	return bson.M{"$regex": pattern}
+}
+
+// LikeToRegex translates a LIKE pattern into an anchored regular expression: % matches any sequence of characters,
+// _ any single character, \ escapes the next character and everything else is matched literally. Runs of % are
+// collapsed, as consecutive .* would make matching backtrack needlessly.
+func LikeToRegex(like string) string {
+	var (
+		b       strings.Builder
+		escaped bool
+		wild    bool
+	)
+
+	b.WriteString("^")
+
+	for _, r := range like {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped, wild = false, false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			if !wild {
+				b.WriteString(".*")
+			}
+			wild = true
+		case r == '_':
+			b.WriteString(".")
+			wild = false
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			wild = false
+		}
+	}
+
+	b.WriteString("$")
+
+	return b.String()
+}
+
+// ObjectIDs converts hex string ids (or slices thereof) into ObjectIDs; other values are returned as is
+func ObjectIDs(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		if oid, err := primitive.ObjectIDFromHex(x); err == nil {
+			return oid
+		}
+	case []string:
+		var ids = make([]interface{}, len(x))
+		for i := range x {
+			ids[i] = ObjectIDs(x[i])
+		}
+		return ids
+	case []interface{}:
+		var ids = make([]interface{}, len(x))
+		for i := range x {
+			ids[i] = ObjectIDs(x[i])
+		}
+		return ids
+	}
+
+	return v
+}
+
+// fromBsonID replaces mongo's _id by id, as a hex string
+func fromBsonID(vals *values.Values) {
+	var v = vals.Get("_id")
+	if v == nil {
+		return
+	}
+
+	if oid, ok := v.Value.(primitive.ObjectID); ok {
+		vals.Set("id", oid.Hex())
+	} else {
+		vals.Set("id", v.Value)
+	}
+
+	vals.Unset("_id")
+}
+
+// Projection returns the projection fetching fields only; _id is excluded unless the id field is part of them
+func Projection(f fields.Fields) bson.D {
+	var (
+		it = f.Iterator()
+		p  = make(bson.D, 0, f.Length()+1)
+	)
+
+	if !f.Contains("id") {
+		p = append(p, bson.E{Key: "_id", Value: 0})
+	}
+
+	for it.Next() {
+		var name = it.Field().Name
+		if name == "id" {
+			name = "_id"
+		}
+
+		p = append(p, bson.E{Key: name, Value: 1})
+	}
+
+	return p
+}
+
 // ValuesToBsonM converts values to Bson that can be used for insert
 func ValuesToBsonM(vals *values.Values) bson.M {
 	if vals == nil {
@@ -5,8 +5,12 @@ import (
 	"testing"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
 )
 
 func compareBsonD(t *testing.T, got, want bson.D) {
@@ -50,7 +54,7 @@ func TestConditionsToBsonD(t *testing.T) {
 				c: []repository.Condition{
 					{
 						Attribute: "name",
-						Operator:  repository.ConditionOperator(10),
+						Operator:  repository.ConditionOperator(99),
 						Value:     "foo",
 					},
 				},
@@ -224,22 +228,103 @@ func TestConditionsToBsonD(t *testing.T) {
 			},
 			wantErr: false,
 		},
-		//{
-		//	name: "Like",
-		//	args: args{
-		//		c: []repository.Condition{
-		//			{
-		//				Attribute: "name",
-		//				Operator:  repository.Like,
-		//				Value:     50,
-		//			},
-		//		},
-		//	},
-		//	want: bson.D{
-		//
-		//	},
-		//	wantErr: false,
-		//},
+		{
+			name: "Id",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "id",
+						Operator:  repository.In,
+						Value:     []interface{}{"5f5b9b9b9b9b9b9b9b9b9b9b", "not-an-object-id"},
+					},
+				},
+			},
+			want: bson.D{
+				bson.E{
+					Key: "_id",
+					Value: bson.M{"$in": []interface{}{
+						primitive.ObjectID{0x5f, 0x5b, 0x9b, 0x9b, 0x9b, 0x9b, 0x9b, 0x9b, 0x9b, 0x9b, 0x9b, 0x9b},
+						"not-an-object-id",
+					}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Like",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "name",
+						Operator:  repository.Like,
+						Value:     `wa_an\%da.%`,
+					},
+				},
+			},
+			want: bson.D{
+				bson.E{
+					Key:   "name",
+					Value: bson.M{"$regex": `^wa.an%da\..*$`, "$options": "s"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Like with runs of %",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "name",
+						Operator:  repository.Like,
+						Value:     `%%wa%\%%%_%%`,
+					},
+				},
+			},
+			want: bson.D{
+				bson.E{
+					Key:   "name",
+					Value: bson.M{"$regex": `^.*wa.*%.*..*$`, "$options": "s"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Contains, StartsWith, IEndsWith",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "name",
+						Operator:  repository.Contains,
+						Value:     ".*",
+					},
+					{
+						Attribute: "code",
+						Operator:  repository.StartsWith,
+						Value:     "(a+)+",
+					},
+					{
+						Attribute: "email",
+						Operator:  repository.IEndsWith,
+						Value:     "@Example.com",
+					},
+				},
+			},
+			want: bson.D{
+				bson.E{
+					Key:   "name",
+					Value: bson.M{"$regex": `\.\*`},
+				},
+				bson.E{
+					Key:   "code",
+					Value: bson.M{"$regex": `^\(a\+\)\+`},
+				},
+				bson.E{
+					Key:   "email",
+					Value: bson.M{"$regex": `@Example\.com$`, "$options": "i"},
+				},
+			},
+			wantErr: false,
+		},
 		//{
 		//	name: "In",
 		//	args: args{
@@ -313,3 +398,67 @@ func TestConditionsToBsonD(t *testing.T) {
 		})
 	}
 }
+
+func TestProjection(t *testing.T) {
+	tests := []struct {
+		name string
+		f    fields.Fields
+		want bson.D
+	}{
+		{
+			name: "With id",
+			f: fields.From(
+				fields.Field{Name: "id", Kind: types.String},
+				fields.Field{Name: "name", Kind: types.String},
+			),
+			want: bson.D{{Key: "_id", Value: 1}, {Key: "name", Value: 1}},
+		},
+		{
+			name: "Without id",
+			f:    fields.From(fields.Field{Name: "price", Kind: types.Float64}),
+			want: bson.D{{Key: "_id", Value: 0}, {Key: "price", Value: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compareBsonD(t, Projection(tt.f), tt.want)
+		})
+	}
+}
+
+func TestFindOptions(t *testing.T) {
+	var opts = FindOptions(repository.Pagination{
+		Offset: 20,
+		Limit:  10,
+		Order:  []repository.OrderBy{{Attribute: "name"}, {Attribute: "age", Sort: repository.Descending}},
+	})
+
+	if opts.Skip == nil || *opts.Skip != 20 || opts.Limit == nil || *opts.Limit != 10 {
+		t.Errorf("FindOptions() skip = %v, limit = %v, want 20, 10", opts.Skip, opts.Limit)
+	}
+
+	if !reflect.DeepEqual(opts.Sort, bson.D{{Key: "name", Value: 1}, {Key: "age", Value: -1}}) {
+		t.Errorf("FindOptions() sort = %v", opts.Sort)
+	}
+
+	if opts = FindOptions(repository.Pagination{}); opts.Skip != nil || opts.Limit != nil || opts.Sort != nil {
+		t.Errorf("FindOptions() without pagination = %+v", opts)
+	}
+}
+
+func TestChangeStreamPipeline(t *testing.T) {
+	var all = bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}}}
+	if got := ChangeStreamPipeline(""); !reflect.DeepEqual(got, mongo.Pipeline{{{Key: "$match", Value: all}}}) {
+		t.Errorf("ChangeStreamPipeline() without tenant = %v", got)
+	}
+
+	var want = bson.M{"$or": bson.A{
+		bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace"}}, "fullDocument.tenant_id": "acme"},
+		bson.M{"operationType": "delete"},
+	}}
+
+	if got := ChangeStreamPipeline("acme"); !reflect.DeepEqual(got, mongo.Pipeline{{{Key: "$match", Value: want}}}) {
+		t.Errorf("ChangeStreamPipeline() of tenant = %v", got)
+	}
+}
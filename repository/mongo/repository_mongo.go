@@ -2,8 +2,6 @@ package mongo
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,11 +17,12 @@ import (
 
 var (
 	// ErrInvalidID is when an invalid ID is passed
-	ErrInvalidID = errors.New("invalid id")
+	ErrInvalidID = repository.ErrInvalidID
 )
 
 func init() {
 	var _ repository.Repositorium = &Repo{}
+	var _ repository.Counter = &Repo{}
 }
 
 type Repo struct {
@@ -51,6 +50,12 @@ func New(dbname, uri string) (repository.Repositorium, error) {
 	return repo, err
 }
 
+// Database returns a repository on another database sharing the client of r, example the database of a tenant;
+// closing it does not disconnect the client
+func (r *Repo) Database(name string) *Repo {
+	return &Repo{db: r.cli.Database(name)}
+}
+
 // Get a single Name by id
 func (r Repo) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
 	var (
@@ -60,7 +65,7 @@ func (r Repo) Get(ctx context.Context, entity entity.Entity, id string) (*values
 
 	var oid, err = primitive.ObjectIDFromHex(id)
 	if err == nil {
-		err = r.db.Collection(entity.Name()).FindOne(ctx, bson.M{"_id": oid}).Decode(&datum)
+		err = r.db.Collection(entity.Name()).FindOne(ctx, bson.M{"_id": oid}, options.FindOne().SetProjection(Projection(entity.Fields()))).Decode(&datum)
 	} else {
 		return nil, ErrInvalidID
 	}
@@ -74,55 +79,29 @@ func (r Repo) Get(ctx context.Context, entity entity.Entity, id string) (*values
 	}
 
 	vals.FromMap(datum)
-	if v := vals.Get("_id"); v != nil {
-		vals.Set("id", v.Value)
-		vals.Unset("_id")
-	}
+	fromBsonID(&vals)
 
 	return &vals, nil
 }
 
 // List multiple Name with pagination rules and conditions
 func (r *Repo) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
-	var (
-		err     error
-		data    []values.Values
-		cursor  *mongo.Cursor
-		filters bson.D
-	)
-
-	filters, err = ConditionsToBsonD(c)
+	var cur, err = r.Stream(ctx, entity, p, c...)
 	if err != nil {
 		return nil, err
 	}
 
-	// todo(fx) pagination
-
-	var f = entity.Fields()
+	return values.All(cur)
+}
 
-	cursor, err = r.db.Collection(entity.Name()).Find(ctx, filters)
+// Count Name satisfying conditions
+func (r *Repo) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	var filters, err = ConditionsToBsonD(c)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	for cursor.Next(ctx) {
-		var datum = f.GetEmptyValues()
-		err = cursor.Decode(datum)
-
-		if err != nil {
-			return nil, err
-		}
-
-		var vals = values.FromMap(datum)
-		if v := vals.Get("_id"); v != nil {
-			vals.Set("id", v.Value)
-			vals.Unset("_id")
-		}
-
-		data = append(data, *vals)
-	}
-
-	return data, err
+	return r.db.Collection(entity.Name()).CountDocuments(ctx, filters)
 }
 
 // Delete a single Name by id
@@ -167,8 +146,9 @@ func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.
 		id   string
 	)
 
+	repository.ApplyDefaults(named, vals)
+
 	if v := vals.Get("id"); v != nil && v.IsString() {
-		fmt.Println("id === ", v.String())
 		var i, e = primitive.ObjectIDFromHex(v.String())
 		if e != nil {
 			return "", e
@@ -182,7 +162,7 @@ func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.
 	rs, err = r.db.Collection(named.Name()).InsertOne(ctx, data)
 
 	if err != nil {
-		return id, err
+		return id, conflict(err)
 	}
 
 	if v, ok := rs.InsertedID.(primitive.ObjectID); ok {
@@ -210,7 +190,7 @@ func (r *Repo) Update(ctx context.Context, named repository.Named, id string, va
 		err = repository.ErrNotFound
 	}
 
-	return err
+	return conflict(err)
 }
 
 // UpdateValuesWhere Values in persistent storage
@@ -228,12 +208,12 @@ func (r *Repo) UpdateWhere(ctx context.Context, named repository.Named, vals *va
 
 	_, err = r.db.Collection(named.Name()).UpdateMany(ctx, filters, data)
 
-	return err
+	return conflict(err)
 }
 
 // Close db connection
 func (r *Repo) Close() error {
-	if r.db == nil {
+	if r.db == nil || r.cli == nil {
 		return nil
 	}
 
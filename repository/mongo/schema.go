@@ -0,0 +1,127 @@
+package mongo
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+// BsonType returns the $jsonSchema bsonType of a field kind; nil if any type is allowed. Integers are accepted both as
+// int and long since the driver encodes a go int fitting in 32 bits as int.
+func BsonType(t types.Type) interface{} {
+	switch t {
+	case types.Bool:
+		return "bool"
+	case types.String:
+		return "string"
+	case types.Int64:
+		return bson.A{"int", "long"}
+	case types.Float64:
+		return "double"
+	}
+
+	return nil
+}
+
+// CreateCollection returns the command to create the collection of an entity, with a $jsonSchema validator
+// ensuring fields have the expected types
+func CreateCollection(e entity.Entity) bson.D {
+	var (
+		f     = e.Fields()
+		it    = f.Iterator()
+		props = bson.D{}
+	)
+
+	for it.Next() {
+		var field = it.Field()
+		if field.Name == "id" {
+			continue // mongo uses its own _id
+		}
+
+		if t := BsonType(field.Kind); t != nil {
+			props = append(props, bson.E{Key: field.Name, Value: bson.D{{Key: "bsonType", Value: t}}})
+		}
+	}
+
+	return bson.D{
+		{Key: "create", Value: e.Name()},
+		{Key: "validator", Value: bson.D{
+			{Key: "$jsonSchema", Value: bson.D{
+				{Key: "bsonType", Value: "object"},
+				{Key: "properties", Value: props},
+			}},
+		}},
+	}
+}
+
+// IndexKeys returns the keys document of an index: 1 for ascending, -1 for descending and "text" for text indexes
+func IndexKeys(i entity.Index) bson.D {
+	var (
+		cols = i.Columns()
+		keys = make(bson.D, len(cols))
+	)
+
+	for j, c := range cols {
+		switch {
+		case i.Text:
+			keys[j] = bson.E{Key: c.Name, Value: "text"}
+		case c.Descending:
+			keys[j] = bson.E{Key: c.Name, Value: -1}
+		default:
+			keys[j] = bson.E{Key: c.Name, Value: 1}
+		}
+	}
+
+	return keys
+}
+
+// IndexModel returns the mongo index model of an index declaration
+func IndexModel(i entity.Index) mongo.IndexModel {
+	var opts = options.Index().SetName(i.IndexName())
+
+	if i.Unique {
+		opts.SetUnique(true)
+	}
+
+	if i.ExpireAfter != 0 {
+		opts.SetExpireAfterSeconds(int32(i.ExpireAfter / time.Second))
+	}
+
+	return mongo.IndexModel{Keys: IndexKeys(i), Options: opts}
+}
+
+// CreateIndexes returns the command to create the declared indexes of an entity; nil if there are none
+func CreateIndexes(e entity.Entity) bson.D {
+	var idx = entity.IndexesOf(e)
+	if len(idx) == 0 {
+		return nil
+	}
+
+	var specs = make(bson.A, len(idx))
+	for j, i := range idx {
+		var spec = bson.D{
+			{Key: "key", Value: IndexKeys(i)},
+			{Key: "name", Value: i.IndexName()},
+		}
+
+		if i.Unique {
+			spec = append(spec, bson.E{Key: "unique", Value: true})
+		}
+
+		if i.ExpireAfter != 0 {
+			spec = append(spec, bson.E{Key: "expireAfterSeconds", Value: int32(i.ExpireAfter / time.Second)})
+		}
+
+		specs[j] = spec
+	}
+
+	return bson.D{
+		{Key: "createIndexes", Value: e.Name()},
+		{Key: "indexes", Value: specs},
+	}
+}
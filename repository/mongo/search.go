@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// scoreField holds the text score of search results
+const scoreField = "__score"
+
+func init() {
+	var _ repository.Searcher = &Repo{}
+}
+
+// SearchOptions returns find options fetching fields and ranking by text score, then by pagination order
+func SearchOptions(p repository.Pagination, f fields.Fields) *options.FindOptions {
+	var (
+		score = bson.M{"$meta": "textScore"}
+		sort  = bson.D{{Key: scoreField, Value: score}}
+		opts  = FindOptions(p).SetProjection(append(Projection(f), bson.E{Key: scoreField, Value: score}))
+	)
+
+	if s, ok := opts.Sort.(bson.D); ok {
+		sort = append(sort, s...)
+	}
+
+	return opts.SetSort(sort)
+}
+
+// Search items matching terms using the text index of the collection, most relevant first
+func (r *Repo) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var cursor *mongo.Cursor
+
+	var filters, err = ConditionsToBsonD(c)
+	if err != nil {
+		return nil, err
+	}
+
+	filters = append(filters, bson.E{Key: "$text", Value: bson.M{"$search": terms}})
+
+	cursor, err = r.db.Collection(entity.Name()).Find(ctx, filters, SearchOptions(p, entity.Fields()))
+	if err != nil {
+		return nil, err
+	}
+
+	return values.All(&Cursor{ctx: ctx, cursor: cursor, fields: entity.Fields()})
+}
@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// Cursor reads values from sql rows one at a time
+type Cursor struct {
+	rows   *sql.Rows
+	fields fields.Fields
+	vals   values.Values
+	err    error
+}
+
+// Next scans the next row
+func (c *Cursor) Next() bool {
+	if c.err != nil || !c.rows.Next() {
+		return false
+	}
+
+	var dst = GetScanDest(c.fields)
+
+	if c.err = c.rows.Scan(dst...); c.err != nil {
+		return false
+	}
+
+	c.vals, c.err = ScanDestToValues(c.fields, dst)
+
+	return c.err == nil
+}
+
+// Value of the current row
+func (c *Cursor) Value() values.Values {
+	return c.vals
+}
+
+// Err returns the error which stopped iteration, if any
+func (c *Cursor) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+
+	return c.rows.Err()
+}
+
+// Close the rows
+func (c *Cursor) Close() error {
+	return c.rows.Close()
+}
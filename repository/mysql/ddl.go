@@ -0,0 +1,155 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+// ColumnType returns the mysql column type used to persist a field kind
+func ColumnType(t types.Type) string {
+	switch t {
+	case types.Bool:
+		return "TINYINT(1)"
+	case types.String:
+		return "VARCHAR(255)"
+	case types.Int64:
+		return "BIGINT"
+	case types.Float64:
+		return "DOUBLE"
+	case types.JSON:
+		return "JSON"
+	}
+
+	return ""
+}
+
+// Column returns the column type of a field and whether it is nullable; id is always a uuid
+func Column(f fields.Field) (string, bool) {
+	if f.Name == "id" {
+		return "VARCHAR(36)", false
+	}
+
+	return ColumnType(f.Kind), f.Kind == types.JSON
+}
+
+// ColumnDefinition returns the definition of a column for a field, example `name` VARCHAR(255) NOT NULL
+func ColumnDefinition(f fields.Field) string {
+	var typ, nullable = Column(f)
+
+	if d := ColumnDefault(f); d != "" {
+		return ColumnSQL(f.Name, typ, nullable) + " DEFAULT " + d
+	}
+
+	return ColumnSQL(f.Name, typ, nullable)
+}
+
+// ColumnDefault returns the literal default of a column for a field declaring a static default, example 'draft'.
+// Computed defaults, defaults of json columns and values not matching the kind of the field have none; repositories
+// apply them on create.
+func ColumnDefault(f fields.Field) string {
+	var s, ok = f.Default.(fields.Static)
+	if !ok || s.V == nil {
+		return ""
+	}
+
+	switch f.Kind {
+	case types.Bool:
+		if b, ok := s.V.(bool); ok && b {
+			return "1"
+		} else if ok {
+			return "0"
+		}
+	case types.String:
+		if str, ok := s.V.(string); ok {
+			return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(str) + "'"
+		}
+	case types.Int64, types.Float64:
+		if x, ok := s.V.(float64); ok {
+			return strconv.FormatFloat(x, 'f', -1, 64)
+		}
+
+		var n = fmt.Sprint(s.V)
+		if _, err := strconv.ParseFloat(n, 64); err == nil {
+			return n
+		}
+	}
+
+	return ""
+}
+
+// ColumnSQL returns the definition of a column, example `name` VARCHAR(255) NOT NULL
+func ColumnSQL(name, typ string, nullable bool) string {
+	if nullable {
+		return "`" + name + "` " + typ + " NULL"
+	}
+
+	return "`" + name + "` " + typ + " NOT NULL"
+}
+
+// IndexDefinition returns the definition of an index within a CREATE TABLE, example KEY `idx_name` (`name`)
+// text indexes are FULLTEXT; expiry is not supported by mysql and ignored
+func IndexDefinition(i entity.Index) string {
+	var (
+		b       strings.Builder
+		columns = i.Columns()
+		cols    = make([]string, len(columns))
+	)
+
+	for j, c := range columns {
+		cols[j] = "`" + c.Name + "`"
+		if c.Descending && !i.Text {
+			cols[j] += " DESC"
+		}
+	}
+
+	if i.Unique {
+		b.WriteString("UNIQUE ")
+	} else if i.Text {
+		b.WriteString("FULLTEXT ")
+	}
+
+	b.WriteString("KEY `")
+	b.WriteString(i.IndexName())
+	b.WriteString("` (")
+	b.WriteString(strings.Join(cols, ","))
+	b.WriteString(")")
+
+	return b.String()
+}
+
+// CreateTable returns a CREATE TABLE statement for an entity, with a primary key on id and its declared indexes
+func CreateTable(e entity.Entity) string {
+	var (
+		name = e.Name()
+		f    = e.Fields()
+		it   = f.Iterator()
+		defs []string
+	)
+
+	if name == "" {
+		return ""
+	}
+
+	if !f.Contains("id") {
+		defs = append(defs, ColumnDefinition(fields.Field{Name: "id", Kind: types.String}))
+	}
+
+	for it.Next() {
+		defs = append(defs, ColumnDefinition(*it.Field()))
+	}
+
+	defs = append(defs, "PRIMARY KEY (`id`)")
+
+	for _, i := range entity.IndexesOf(e) {
+		defs = append(defs, IndexDefinition(i))
+	}
+
+	return "CREATE TABLE `" + name + "` (\n  " +
+		strings.Join(defs, ",\n  ") +
+		"\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;"
+}
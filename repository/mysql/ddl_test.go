@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+func TestCreateTable(t *testing.T) {
+	tests := []struct {
+		name string
+		e    entity.Entity
+		want string
+	}{
+		{
+			name: "No name",
+			e:    entity.Spec{},
+			want: "",
+		},
+		{
+			name: "Implicit id",
+			e: entity.Spec{
+				EntityName: "country",
+				FieldSpecs: []entity.FieldSpec{
+					{Name: "name", Kind: types.String},
+				},
+			},
+			want: "CREATE TABLE `country` (\n" +
+				"  `id` VARCHAR(36) NOT NULL,\n" +
+				"  `name` VARCHAR(255) NOT NULL,\n" +
+				"  PRIMARY KEY (`id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+		},
+		{
+			name: "All kinds with indexes",
+			e: entity.Spec{
+				EntityName: "product",
+				FieldSpecs: []entity.FieldSpec{
+					{Name: "id", Kind: types.String},
+					{Name: "sku", Kind: types.String},
+					{Name: "active", Kind: types.Bool},
+					{Name: "stock", Kind: types.Int64},
+					{Name: "price", Kind: types.Float64},
+					{Name: "attrs", Kind: types.JSON},
+				},
+				IndexSpecs: []entity.IndexSpec{
+					{Fields: []string{"sku"}, Unique: true},
+					{Name: "by_stock", Fields: []string{"active", "-stock"}},
+					{Fields: []string{"sku"}, Text: true},
+				},
+			},
+			want: "CREATE TABLE `product` (\n" +
+				"  `id` VARCHAR(36) NOT NULL,\n" +
+				"  `sku` VARCHAR(255) NOT NULL,\n" +
+				"  `active` TINYINT(1) NOT NULL,\n" +
+				"  `stock` BIGINT NOT NULL,\n" +
+				"  `price` DOUBLE NOT NULL,\n" +
+				"  `attrs` JSON NULL,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `uniq_sku` (`sku`),\n" +
+				"  KEY `by_stock` (`active`,`stock` DESC),\n" +
+				"  FULLTEXT KEY `text_sku` (`sku`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+		},
+		{
+			name: "Defaults",
+			e: ent{
+				name: "article",
+				fields: fields.From(
+					fields.Field{Name: "title", Kind: types.String},
+					fields.Field{Name: "slug", Kind: types.String, Default: fields.SlugOf("title")},
+					fields.Field{Name: "status", Kind: types.String, Default: fields.Static{V: "it's new"}},
+					fields.Field{Name: "pinned", Kind: types.Bool, Default: fields.Static{V: false}},
+					fields.Field{Name: "views", Kind: types.Int64, Default: fields.Static{V: float64(1000000)}},
+					fields.Field{Name: "rating", Kind: types.Float64, Default: fields.Static{V: 2.5}},
+					fields.Field{Name: "meta", Kind: types.JSON, Default: fields.Static{V: map[string]interface{}{}}},
+				),
+			},
+			want: "CREATE TABLE `article` (\n" +
+				"  `id` VARCHAR(36) NOT NULL,\n" +
+				"  `title` VARCHAR(255) NOT NULL,\n" +
+				"  `slug` VARCHAR(255) NOT NULL,\n" +
+				"  `status` VARCHAR(255) NOT NULL DEFAULT 'it''s new',\n" +
+				"  `pinned` TINYINT(1) NOT NULL DEFAULT 0,\n" +
+				"  `views` BIGINT NOT NULL DEFAULT 1000000,\n" +
+				"  `rating` DOUBLE NOT NULL DEFAULT 2.5,\n" +
+				"  `meta` JSON NULL,\n" +
+				"  PRIMARY KEY (`id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+		},
+		{
+			name: "Searchable",
+			e: entity.Spec{
+				EntityName:  "article",
+				FieldSpecs:  []entity.FieldSpec{{Name: "title", Kind: types.String}},
+				SearchSpecs: []string{"title"},
+			},
+			want: "CREATE TABLE `article` (\n" +
+				"  `id` VARCHAR(36) NOT NULL,\n" +
+				"  `title` VARCHAR(255) NOT NULL,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  FULLTEXT KEY `text_search` (`title`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CreateTable(tt.e); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
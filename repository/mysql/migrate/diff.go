@@ -0,0 +1,154 @@
+package migrate
+
+import (
+	"strings"
+
+	"github.com/fluxynet/gocipe/repository/mysql"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+// Diff returns statements to migrate a schema from one state to another (up) and back (down). Tables of from which are
+// not in to are dropped only if managed, typically as recorded by the last snapshot: tables which are not declared as
+// entities, such as those of an outbox or of other applications, are left alone.
+func Diff(from, to, managed Schema) (up []string, down []string) {
+	for _, t := range to.Tables {
+		var old = from.Table(t.Name)
+
+		if old == nil {
+			up = append(up, CreateTable(t))
+			down = append([]string{DropTable(t)}, down...)
+			continue
+		}
+
+		var u, d = diffTable(*old, t)
+		up = append(up, u...)
+		down = append(d, down...)
+	}
+
+	for _, t := range from.Tables {
+		if to.Table(t.Name) == nil && managed.Table(t.Name) != nil {
+			up = append(up, DropTable(t))
+			down = append([]string{CreateTable(t)}, down...)
+		}
+	}
+
+	return up, down
+}
+
+// step of a migration and the statement undoing it
+type step struct {
+	up, down string
+}
+
+// diffTable returns statements altering columns and indexes of a table. Indexes are dropped before columns and added
+// after them, so that no index refers to a missing column; down statements undo up statements in reverse order.
+func diffTable(from, to Table) (up []string, down []string) {
+	var (
+		steps []step
+		alter = "ALTER TABLE `" + to.Name + "` "
+	)
+
+	for _, i := range from.Indexes {
+		if x := to.Index(i.Name); x == nil || !sameIndex(i, *x) {
+			steps = append(steps, step{
+				up:   alter + "DROP INDEX `" + i.Name + "`;",
+				down: alter + "ADD " + indexSQL(i) + ";",
+			})
+		}
+	}
+
+	for i, c := range to.Columns {
+		var old = from.Column(c.Name)
+
+		if old == nil {
+			steps = append(steps, step{
+				up:   alter + "ADD COLUMN " + mysql.ColumnSQL(c.Name, strings.ToUpper(c.Type), c.Nullable) + position(to, i) + ";",
+				down: alter + "DROP COLUMN `" + c.Name + "`;",
+			})
+		} else if old.Type != c.Type || old.Nullable != c.Nullable {
+			steps = append(steps, step{
+				up:   alter + "MODIFY COLUMN " + mysql.ColumnSQL(c.Name, strings.ToUpper(c.Type), c.Nullable) + ";",
+				down: alter + "MODIFY COLUMN " + mysql.ColumnSQL(old.Name, strings.ToUpper(old.Type), old.Nullable) + ";",
+			})
+		}
+	}
+
+	// dropped last first, so that undoing restores them first to last, each after its predecessor
+	for i := len(from.Columns) - 1; i >= 0; i-- {
+		var c = from.Columns[i]
+
+		if to.Column(c.Name) == nil {
+			steps = append(steps, step{
+				up:   alter + "DROP COLUMN `" + c.Name + "`;",
+				down: alter + "ADD COLUMN " + mysql.ColumnSQL(c.Name, strings.ToUpper(c.Type), c.Nullable) + position(from, i) + ";",
+			})
+		}
+	}
+
+	for _, i := range to.Indexes {
+		if x := from.Index(i.Name); x == nil || !sameIndex(*x, i) {
+			steps = append(steps, step{
+				up:   alter + "ADD " + indexSQL(i) + ";",
+				down: alter + "DROP INDEX `" + i.Name + "`;",
+			})
+		}
+	}
+
+	for k := range steps {
+		up = append(up, steps[k].up)
+		down = append(down, steps[len(steps)-1-k].down)
+	}
+
+	return up, down
+}
+
+// position clause of the column at index i of a table
+func position(t Table, i int) string {
+	if i == 0 {
+		return " FIRST"
+	}
+
+	return " AFTER `" + t.Columns[i-1].Name + "`"
+}
+
+// CreateTable statement for a table
+func CreateTable(t Table) string {
+	var defs []string
+
+	for _, c := range t.Columns {
+		defs = append(defs, mysql.ColumnSQL(c.Name, strings.ToUpper(c.Type), c.Nullable))
+	}
+
+	defs = append(defs, "PRIMARY KEY (`id`)")
+
+	for _, i := range t.Indexes {
+		defs = append(defs, indexSQL(i))
+	}
+
+	return "CREATE TABLE `" + t.Name + "` (\n  " +
+		strings.Join(defs, ",\n  ") +
+		"\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;"
+}
+
+// DropTable statement for a table
+func DropTable(t Table) string {
+	return "DROP TABLE `" + t.Name + "`;"
+}
+
+func indexSQL(i Index) string {
+	return mysql.IndexDefinition(entity.Index{Name: i.Name, Fields: i.Columns, Unique: i.Unique, Text: i.Text})
+}
+
+func sameIndex(a, b Index) bool {
+	if a.Unique != b.Unique || a.Text != b.Text || len(a.Columns) != len(b.Columns) {
+		return false
+	}
+
+	for i := range a.Columns {
+		if a.Columns[i] != b.Columns[i] {
+			return false
+		}
+	}
+
+	return true
+}
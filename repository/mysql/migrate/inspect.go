@@ -0,0 +1,119 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Inspect the live schema of the current database via information_schema; the bookkeeping table is excluded
+func Inspect(ctx context.Context, db *sql.DB) (Schema, error) {
+	var s Schema
+
+	var rs, err = db.QueryContext(
+		ctx,
+		"SELECT `TABLE_NAME`, `COLUMN_NAME`, `COLUMN_TYPE`, `IS_NULLABLE` FROM `information_schema`.`COLUMNS` "+
+			"WHERE `TABLE_SCHEMA` = DATABASE() AND `TABLE_NAME` <> ? ORDER BY `TABLE_NAME`, `ORDINAL_POSITION`",
+		BookkeepingTable,
+	)
+
+	if err != nil {
+		return s, err
+	}
+
+	err = each(rs, func() error {
+		var (
+			table, nullable string
+			c               Column
+		)
+
+		if err := rs.Scan(&table, &c.Name, &c.Type, &nullable); err != nil {
+			return err
+		}
+
+		c.Type = Normalize(c.Type)
+		c.Nullable = nullable == "YES"
+
+		var t = s.Table(table)
+		if t == nil {
+			s.Tables = append(s.Tables, Table{Name: table})
+			t = &s.Tables[len(s.Tables)-1]
+		}
+
+		t.Columns = append(t.Columns, c)
+		return nil
+	})
+
+	if err == nil {
+		err = inspectIndexes(ctx, db, &s)
+	}
+
+	if err != nil {
+		return Schema{}, err
+	}
+
+	s.sort()
+
+	return s, nil
+}
+
+// inspectIndexes adds indexes other than primary keys to tables of a schema
+func inspectIndexes(ctx context.Context, db *sql.DB, s *Schema) error {
+	var rs, err = db.QueryContext(
+		ctx,
+		"SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `INDEX_TYPE`, COALESCE(`COLLATION`, '') "+
+			"FROM `information_schema`.`STATISTICS` "+
+			"WHERE `TABLE_SCHEMA` = DATABASE() AND `INDEX_NAME` <> 'PRIMARY' "+
+			"ORDER BY `TABLE_NAME`, `INDEX_NAME`, `SEQ_IN_INDEX`",
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return each(rs, func() error {
+		var (
+			table, name, column, typ, collation string
+			nonUnique                           int
+		)
+
+		if err := rs.Scan(&table, &name, &column, &nonUnique, &typ, &collation); err != nil {
+			return err
+		}
+
+		if collation == "D" {
+			column = "-" + column
+		}
+
+		var t = s.Table(table)
+		if t == nil {
+			return nil
+		}
+
+		var i = t.Index(name)
+		if i == nil {
+			t.Indexes = append(t.Indexes, Index{Name: name, Unique: nonUnique == 0, Text: typ == "FULLTEXT"})
+			i = &t.Indexes[len(t.Indexes)-1]
+		}
+
+		i.Columns = append(i.Columns, column)
+		return nil
+	})
+}
+
+// each row of a result set is handled by fn until it fails; rows are closed and the first error encountered is returned
+func each(rs *sql.Rows, fn func() error) error {
+	var err error
+	for err == nil && rs.Next() {
+		err = fn()
+	}
+
+	if err == nil {
+		err = rs.Err()
+	}
+
+	if cerr := rs.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
@@ -0,0 +1,132 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository/sqltest"
+)
+
+// state of a stand-in database
+type fakeDB struct {
+	columns    [][]driver.Value
+	statistics [][]driver.Value
+	versions   map[string]string
+	executed   []string
+	fail       string
+}
+
+// open a stand-in database for the duration of a test
+func open(t *testing.T, db *fakeDB) *sql.DB {
+	if db.versions == nil {
+		db.versions = make(map[string]string)
+	}
+
+	var s = sqltest.DB{Query: db.query, Exec: db.exec}
+	return s.Open(t)
+}
+
+func (db *fakeDB) exec(query string, args []driver.Value) error {
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS `"+BookkeepingTable+"`"):
+	case strings.HasPrefix(query, "INSERT INTO `"+BookkeepingTable+"`"):
+		db.versions[args[0].(string)] = "2021-03-04 05:06:07"
+	case strings.HasPrefix(query, "DELETE FROM `"+BookkeepingTable+"`"):
+		delete(db.versions, args[0].(string))
+	case query == db.fail:
+		return errors.New("failed: " + query)
+	default:
+		db.executed = append(db.executed, query)
+	}
+
+	return nil
+}
+
+func (db *fakeDB) query(query string, args []driver.Value) (*sqltest.Rows, error) {
+	switch {
+	case strings.Contains(query, "`information_schema`.`COLUMNS`"):
+		return &sqltest.Rows{Columns: make([]string, 4), Values: db.columns}, nil
+	case strings.Contains(query, "`information_schema`.`STATISTICS`"):
+		return &sqltest.Rows{Columns: make([]string, 6), Values: db.statistics}, nil
+	case strings.Contains(query, "FROM `"+BookkeepingTable+"`"):
+		var rows [][]driver.Value
+		for v, t := range db.versions {
+			rows = append(rows, []driver.Value{v, []byte(t)})
+		}
+
+		return &sqltest.Rows{Columns: make([]string, 2), Values: rows}, nil
+	}
+
+	return nil, errors.New("unexpected query " + query)
+}
+
+func TestInspect(t *testing.T) {
+	var columns = [][]driver.Value{
+		{"order", "id", "varchar(36)", "NO"},
+		{"customer", "id", "varchar(36)", "NO"},
+		{"customer", "name", "VARCHAR(255)", "YES"},
+		{"customer", "age", "int(11)", "NO"},
+	}
+
+	tests := []struct {
+		name       string
+		statistics [][]driver.Value
+		want       Schema
+		wantErr    bool
+	}{
+		{
+			name: "Tables and indexes",
+			statistics: [][]driver.Value{
+				{"customer", "idx_name_age", "name", int64(1), "BTREE", "A"},
+				{"customer", "idx_name_age", "age", int64(1), "BTREE", "D"},
+				{"customer", "uq_name", "name", int64(0), "BTREE", "A"},
+				{"unknown", "idx_x", "x", int64(1), "BTREE", "A"},
+			},
+			want: Schema{Tables: []Table{
+				{
+					Name: "customer",
+					Columns: []Column{
+						{Name: "id", Type: "varchar(36)"},
+						{Name: "name", Type: "varchar(255)", Nullable: true},
+						{Name: "age", Type: "int"},
+					},
+					Indexes: []Index{
+						{Name: "idx_name_age", Columns: []string{"name", "-age"}},
+						{Name: "uq_name", Columns: []string{"name"}, Unique: true},
+					},
+				},
+				{
+					Name:    "order",
+					Columns: []Column{{Name: "id", Type: "varchar(36)"}},
+				},
+			}},
+		},
+		{
+			name: "Scan error of indexes",
+			statistics: [][]driver.Value{
+				{"customer", "idx_name", "name", "not a number", "BTREE", "A"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var db = open(t, &fakeDB{columns: columns, statistics: tt.statistics})
+
+			var got, err = Inspect(context.Background(), db)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Inspect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Inspect()\n\tgot  = %#v\n\twant = %#v", got, tt.want)
+			}
+		})
+	}
+}
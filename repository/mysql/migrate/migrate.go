@@ -0,0 +1,298 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BookkeepingTable keeps track of applied migrations
+const BookkeepingTable = "schema_migrations"
+
+// VersionFormat is the timestamp format of migration versions
+const VersionFormat = "20060102150405"
+
+var (
+	// ErrNoChanges is when a diff yields no statements
+	ErrNoChanges = errors.New("no schema changes")
+
+	// ErrInvalidName is when a migration name contains characters other than letters, digits and underscores
+	ErrInvalidName = errors.New("invalid migration name")
+)
+
+// Migration is a pair of up and down scripts identified by a timestamped version
+type Migration struct {
+	Version string
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status of a migration wrt a database
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Generate writes migration files <version>_<name>.up.sql and <version>_<name>.down.sql in dir
+func Generate(dir, name string, up, down []string, now time.Time) (Migration, error) {
+	var m = Migration{
+		Version: now.UTC().Format(VersionFormat),
+		Name:    name,
+		Up:      strings.Join(up, "\n\n") + "\n",
+		Down:    strings.Join(down, "\n\n") + "\n",
+	}
+
+	if len(up) == 0 {
+		return m, ErrNoChanges
+	}
+
+	for _, r := range name {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return m, fmt.Errorf("%w: %s", ErrInvalidName, name)
+		}
+	}
+
+	var err = os.MkdirAll(dir, 0755)
+
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, m.Version+"_"+name+".up.sql"), []byte(m.Up), 0644)
+	}
+
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, m.Version+"_"+name+".down.sql"), []byte(m.Down), 0644)
+	}
+
+	return m, err
+}
+
+// Load migrations from dir, sorted by version
+func Load(dir string) ([]Migration, error) {
+	var files, err = filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	var byVersion = make(map[string]*Migration)
+
+	for _, f := range files {
+		var base = filepath.Base(f)
+
+		var direction string
+		if strings.HasSuffix(base, ".up.sql") {
+			direction = "up"
+		} else if strings.HasSuffix(base, ".down.sql") {
+			direction = "down"
+		} else {
+			continue
+		}
+
+		var p = strings.SplitN(strings.TrimSuffix(base, "."+direction+".sql"), "_", 2)
+		if len(p) != 2 || len(p[0]) != len(VersionFormat) {
+			continue
+		}
+
+		var b []byte
+		if b, err = os.ReadFile(f); err != nil {
+			return nil, err
+		}
+
+		var m = byVersion[p[0]]
+		if m == nil {
+			m = &Migration{Version: p[0], Name: p[1]}
+			byVersion[p[0]] = m
+		}
+
+		if direction == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	var migrations = make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Statements of a script, separated by semicolons at end of lines
+func Statements(script string) []string {
+	var stmts []string
+
+	for _, s := range strings.Split(script, ";\n") {
+		if s = strings.TrimSuffix(strings.TrimSpace(s), ";"); s != "" {
+			stmts = append(stmts, s)
+		}
+	}
+
+	return stmts
+}
+
+// Migrator applies migrations on a database, keeping track of them in the bookkeeping table
+type Migrator struct {
+	DB *sql.DB
+}
+
+// init creates the bookkeeping table if needed
+func (m Migrator) init(ctx context.Context) error {
+	var _, err = m.DB.ExecContext(
+		ctx,
+		"CREATE TABLE IF NOT EXISTS `"+BookkeepingTable+"` ("+
+			"`version` VARCHAR(14) NOT NULL, "+
+			"`name` VARCHAR(255) NOT NULL, "+
+			"`applied_at` DATETIME NOT NULL, "+
+			"PRIMARY KEY (`version`)"+
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	)
+
+	return err
+}
+
+// applied returns versions applied along with the time they were applied
+func (m Migrator) applied(ctx context.Context) (map[string]time.Time, error) {
+	if err := m.init(ctx); err != nil {
+		return nil, err
+	}
+
+	var rs, err = m.DB.QueryContext(ctx, "SELECT `version`, `applied_at` FROM `"+BookkeepingTable+"`")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions = make(map[string]time.Time)
+	err = each(rs, func() error {
+		var (
+			v string
+			t []byte
+		)
+
+		if err := rs.Scan(&v, &t); err != nil {
+			return err
+		}
+
+		versions[v] = parseTime(string(t))
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// Status of each migration
+func (m Migrator) Status(ctx context.Context, migrations []Migration) ([]Status, error) {
+	var applied, err = m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var s = make([]Status, len(migrations))
+	for i := range migrations {
+		s[i].Migration = migrations[i]
+
+		if t, ok := applied[migrations[i].Version]; ok {
+			s[i].AppliedAt = &t
+		}
+	}
+
+	return s, nil
+}
+
+// Up applies pending migrations in order, returning those applied. Stops at the first failure.
+func (m Migrator) Up(ctx context.Context, migrations []Migration) ([]Migration, error) {
+	var applied, err = m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+
+		if err = m.exec(ctx, mig.Up); err != nil {
+			return done, fmt.Errorf("migration %s_%s: %w", mig.Version, mig.Name, err)
+		}
+
+		_, err = m.DB.ExecContext(
+			ctx,
+			"INSERT INTO `"+BookkeepingTable+"` (`version`, `name`, `applied_at`) VALUES (?, ?, UTC_TIMESTAMP())",
+			mig.Version,
+			mig.Name,
+		)
+
+		if err != nil {
+			return done, err
+		}
+
+		done = append(done, mig)
+	}
+
+	return done, nil
+}
+
+// Down reverts the latest applied migrations, up to steps, returning those reverted
+func (m Migrator) Down(ctx context.Context, migrations []Migration, steps int) ([]Migration, error) {
+	var applied, err = m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		var mig = migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+
+		if err = m.exec(ctx, mig.Down); err != nil {
+			return done, fmt.Errorf("migration %s_%s: %w", mig.Version, mig.Name, err)
+		}
+
+		_, err = m.DB.ExecContext(ctx, "DELETE FROM `"+BookkeepingTable+"` WHERE `version` = ?", mig.Version)
+		if err != nil {
+			return done, err
+		}
+
+		done = append(done, mig)
+	}
+
+	return done, nil
+}
+
+// exec statements of a script one by one; mysql commits DDL implicitly so scripts are not transactional
+func (m Migrator) exec(ctx context.Context, script string) error {
+	for _, stmt := range Statements(script) {
+		if _, err := m.DB.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseTime of a DATETIME as returned by the driver, depending on whether parseTime is enabled in the dsn
+func parseTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t
+	}
+
+	var t, _ = time.Parse("2006-01-02 15:04:05", s)
+	return t
+}
@@ -0,0 +1,242 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	var (
+		customer = Table{
+			Name: "customer",
+			Columns: []Column{
+				{Name: "id", Type: "varchar(36)"},
+				{Name: "email", Type: "varchar(255)"},
+			},
+		}
+
+		customerV2 = Table{
+			Name: "customer",
+			Columns: []Column{
+				{Name: "id", Type: "varchar(36)"},
+				{Name: "name", Type: "varchar(255)"},
+				{Name: "email", Type: "json", Nullable: true},
+			},
+			Indexes: []Index{
+				{Name: "idx_name", Columns: []string{"name"}},
+			},
+		}
+
+		order = Table{
+			Name: "order",
+			Columns: []Column{
+				{Name: "id", Type: "varchar(36)"},
+			},
+		}
+	)
+
+	tests := []struct {
+		name     string
+		from     Schema
+		to       Schema
+		managed  Schema
+		wantUp   []string
+		wantDown []string
+	}{
+		{
+			name: "No changes",
+			from: Schema{Tables: []Table{customer}},
+			to:   Schema{Tables: []Table{customer}},
+		},
+		{
+			name: "New table",
+			from: Schema{},
+			to:   Schema{Tables: []Table{order}},
+			wantUp: []string{
+				"CREATE TABLE `order` (\n  `id` VARCHAR(36) NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+			},
+			wantDown: []string{
+				"DROP TABLE `order`;",
+			},
+		},
+		{
+			name:    "Dropped table",
+			from:    Schema{Tables: []Table{order}},
+			to:      Schema{},
+			managed: Schema{Tables: []Table{order}},
+			wantUp:  []string{"DROP TABLE `order`;"},
+			wantDown: []string{
+				"CREATE TABLE `order` (\n  `id` VARCHAR(36) NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+			},
+		},
+		{
+			name: "Unmanaged table",
+			from: Schema{Tables: []Table{customer, order}},
+			to:   Schema{Tables: []Table{customer}},
+		},
+		{
+			name: "Altered table",
+			from: Schema{Tables: []Table{customer}},
+			to:   Schema{Tables: []Table{customerV2}},
+			wantUp: []string{
+				"ALTER TABLE `customer` ADD COLUMN `name` VARCHAR(255) NOT NULL AFTER `id`;",
+				"ALTER TABLE `customer` MODIFY COLUMN `email` JSON NULL;",
+				"ALTER TABLE `customer` ADD KEY `idx_name` (`name`);",
+			},
+			wantDown: []string{
+				"ALTER TABLE `customer` DROP INDEX `idx_name`;",
+				"ALTER TABLE `customer` MODIFY COLUMN `email` VARCHAR(255) NOT NULL;",
+				"ALTER TABLE `customer` DROP COLUMN `name`;",
+			},
+		},
+		{
+			name: "Dropped indexed columns",
+			from: Schema{Tables: []Table{customerV2}},
+			to:   Schema{Tables: []Table{{Name: "customer", Columns: customer.Columns[:1]}}},
+			wantUp: []string{
+				"ALTER TABLE `customer` DROP INDEX `idx_name`;",
+				"ALTER TABLE `customer` DROP COLUMN `email`;",
+				"ALTER TABLE `customer` DROP COLUMN `name`;",
+			},
+			wantDown: []string{
+				"ALTER TABLE `customer` ADD COLUMN `name` VARCHAR(255) NOT NULL AFTER `id`;",
+				"ALTER TABLE `customer` ADD COLUMN `email` JSON NULL AFTER `name`;",
+				"ALTER TABLE `customer` ADD KEY `idx_name` (`name`);",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var up, down = Diff(tt.from, tt.to, tt.managed)
+
+			if !reflect.DeepEqual(up, tt.wantUp) {
+				t.Errorf("Diff() up\n\tgot  = %q\n\twant = %q", up, tt.wantUp)
+			}
+
+			if !reflect.DeepEqual(down, tt.wantDown) {
+				t.Errorf("Diff() down\n\tgot  = %q\n\twant = %q", down, tt.wantDown)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{typ: "BIGINT", want: "bigint"},
+		{typ: "bigint(20)", want: "bigint"},
+		{typ: "tinyint(1)", want: "tinyint(1)"},
+		{typ: "VARCHAR(36)", want: "varchar(36)"},
+		{typ: "int(11) unsigned", want: "int unsigned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			if got := Normalize(tt.typ); got != tt.want {
+				t.Errorf("Normalize() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateLoad(t *testing.T) {
+	var (
+		dir = t.TempDir()
+		now = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	)
+
+	if _, err := Generate(dir, "noop", nil, nil, now); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("Generate() error = %v, want ErrNoChanges", err)
+	}
+
+	if _, err := Generate(dir, "bad name", []string{"SELECT 1;"}, nil, now); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Generate() error = %v, want ErrInvalidName", err)
+	}
+
+	var _, err = Generate(dir, "second", []string{"DROP TABLE `b`;"}, []string{"CREATE TABLE `b` (`id` INT);"}, now.Add(time.Hour))
+	if err == nil {
+		_, err = Generate(dir, "first", []string{"CREATE TABLE `a` (`id` INT);", "SELECT 1;"}, []string{"DROP TABLE `a`;"}, now)
+	}
+
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var got []Migration
+	if got, err = Load(dir); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var want = []Migration{
+		{Version: "20210304050607", Name: "first", Up: "CREATE TABLE `a` (`id` INT);\n\nSELECT 1;\n", Down: "DROP TABLE `a`;\n"},
+		{Version: "20210304060607", Name: "second", Up: "DROP TABLE `b`;\n", Down: "CREATE TABLE `b` (`id` INT);\n"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load()\n\tgot  = %#v\n\twant = %#v", got, want)
+	}
+
+	if stmts := Statements(got[0].Up); !reflect.DeepEqual(stmts, []string{"CREATE TABLE `a` (`id` INT)", "SELECT 1"}) {
+		t.Errorf("Statements() = %q", stmts)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		migrations = []Migration{
+			{Version: "20210304050607", Name: "first", Up: "CREATE TABLE `a` (`id` INT);\n\nSELECT 1;\n", Down: "DROP TABLE `a`;\n"},
+			{Version: "20210304060607", Name: "second", Up: "CREATE TABLE `b` (`id` INT);\n", Down: "DROP TABLE `b`;\n"},
+			{Version: "20210304070607", Name: "third", Up: "CREATE TABLE `c` (`id` INT);\n", Down: "DROP TABLE `c`;\n"},
+		}
+		state = &fakeDB{
+			versions: map[string]string{"20210304050607": "2021-03-04 05:06:07"},
+			fail:     "CREATE TABLE `c` (`id` INT)",
+		}
+		m = Migrator{DB: open(t, state)}
+	)
+
+	var done, err = m.Up(ctx, migrations)
+	if err == nil || !reflect.DeepEqual(done, migrations[1:2]) {
+		t.Errorf("Up() = %v, %v; want second and failure of third", done, err)
+	}
+
+	if want := []string{"CREATE TABLE `b` (`id` INT)"}; !reflect.DeepEqual(state.executed, want) {
+		t.Errorf("Up() executed %q, want %q", state.executed, want)
+	}
+
+	var status []Status
+	if status, err = m.Status(ctx, migrations); err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	var applied = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	for i, s := range status {
+		if s.Migration != migrations[i] {
+			t.Errorf("Status()[%d] = %v, want %v", i, s.Migration, migrations[i])
+		}
+
+		if pending := i == 2; (s.AppliedAt == nil) != pending || (!pending && !s.AppliedAt.Equal(applied)) {
+			t.Errorf("Status()[%d] applied at %v", i, s.AppliedAt)
+		}
+	}
+
+	state.executed = nil
+	if done, err = m.Down(ctx, migrations, 1); err != nil || !reflect.DeepEqual(done, migrations[1:2]) {
+		t.Errorf("Down() = %v, %v; want second", done, err)
+	}
+
+	if want := []string{"DROP TABLE `b`"}; !reflect.DeepEqual(state.executed, want) {
+		t.Errorf("Down() executed %q, want %q", state.executed, want)
+	}
+
+	if want := map[string]string{"20210304050607": "2021-03-04 05:06:07"}; !reflect.DeepEqual(state.versions, want) {
+		t.Errorf("Down() versions = %v, want %v", state.versions, want)
+	}
+}
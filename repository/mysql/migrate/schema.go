@@ -0,0 +1,161 @@
+package migrate
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fluxynet/gocipe/repository/mysql"
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+)
+
+// Column of a table
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable,omitempty"`
+}
+
+// Index of a table (excluding the primary key); descending columns are prefixed by -
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+	Text    bool     `json:"text,omitempty"`
+}
+
+// Table definition
+type Table struct {
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+	Indexes []Index  `json:"indexes,omitempty"`
+}
+
+// Schema is a set of tables, sorted by name
+type Schema struct {
+	Tables []Table `json:"tables"`
+}
+
+// Table by name, nil if not present
+func (s Schema) Table(name string) *Table {
+	for i := range s.Tables {
+		if s.Tables[i].Name == name {
+			return &s.Tables[i]
+		}
+	}
+
+	return nil
+}
+
+// Column by name, nil if not present
+func (t Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+
+	return nil
+}
+
+// Index by name, nil if not present
+func (t Table) Index(name string) *Index {
+	for i := range t.Indexes {
+		if t.Indexes[i].Name == name {
+			return &t.Indexes[i]
+		}
+	}
+
+	return nil
+}
+
+// FromEntities returns the schema expected for entities, as generated by mysql.CreateTable
+func FromEntities(entities entity.Entities) Schema {
+	var s Schema
+
+	for _, e := range entities {
+		var (
+			t  = Table{Name: e.Name()}
+			f  = e.Fields()
+			it = f.Iterator()
+		)
+
+		if !f.Contains("id") {
+			var typ, nullable = mysql.Column(fields.Field{Name: "id", Kind: types.String})
+			t.Columns = append(t.Columns, Column{Name: "id", Type: Normalize(typ), Nullable: nullable})
+		}
+
+		for it.Next() {
+			var typ, nullable = mysql.Column(*it.Field())
+			t.Columns = append(t.Columns, Column{Name: it.Field().Name, Type: Normalize(typ), Nullable: nullable})
+		}
+
+		for _, i := range entity.IndexesOf(e) {
+			var cols = make([]string, len(i.Fields))
+			for j, c := range i.Columns() {
+				if cols[j] = c.Name; c.Descending && !i.Text {
+					cols[j] = "-" + c.Name
+				}
+			}
+
+			t.Indexes = append(t.Indexes, Index{Name: i.IndexName(), Columns: cols, Unique: i.Unique, Text: i.Text})
+		}
+
+		s.Tables = append(s.Tables, t)
+	}
+
+	s.sort()
+
+	return s
+}
+
+func (s *Schema) sort() {
+	sort.Slice(s.Tables, func(i, j int) bool {
+		return s.Tables[i].Name < s.Tables[j].Name
+	})
+
+	for i := range s.Tables {
+		var idx = s.Tables[i].Indexes
+		sort.Slice(idx, func(a, b int) bool {
+			return idx[a].Name < idx[b].Name
+		})
+	}
+}
+
+var intWidth = regexp.MustCompile(`^(bigint|int|smallint|mediumint)\(\d+\)`)
+
+// Normalize a column type for comparison, as reported by information_schema. Integer display widths are ignored,
+// except for tinyint(1) which denotes booleans.
+func Normalize(typ string) string {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	return intWidth.ReplaceAllString(typ, "$1")
+}
+
+// LoadSnapshot reads a schema snapshot; an empty schema is returned if the file does not exist
+func LoadSnapshot(filename string) (Schema, error) {
+	var s Schema
+
+	var b, err = os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return s, err
+	}
+
+	err = json.Unmarshal(b, &s)
+	return s, err
+}
+
+// SaveSnapshot writes a schema snapshot
+func SaveSnapshot(filename string, s Schema) error {
+	var b, err = json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, b, 0644)
+}
@@ -1,6 +1,11 @@
 package mysql
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -72,7 +77,8 @@ func Operator(op repository.ConditionOperator) string {
 		return "<"
 	case repository.LessOrEqualTo:
 		return "<="
-	case repository.Like:
+	case repository.Like, repository.Contains, repository.StartsWith, repository.EndsWith,
+		repository.IContains, repository.IStartsWith, repository.IEndsWith:
 		return "LIKE"
 	case repository.In:
 		return "IN"
@@ -117,28 +123,204 @@ func ConditionsToWhere(c []repository.Condition) (string, []interface{}) {
 	}
 
 	var where strings.Builder
-	var args = make([]interface{}, t)
+	var args = make([]interface{}, 0, t)
 
 	where.WriteString(" WHERE ")
 
 	t -= 1
 	for i := range c {
-		where.WriteString("`")
-		where.WriteString(c[i].Attribute)
-		where.WriteString("` ")
-		where.WriteString(Operator(c[i].Operator))
-		where.WriteString(" ?")
+		if list, ok := inList(c[i]); ok {
+			where.WriteString(inToSQL(c[i], len(list)))
+			args = append(args, list...)
+		} else if c[i].Operator.IsPattern() {
+			var expr, arg = Pattern(c[i])
+			where.WriteString(expr)
+			args = append(args, arg)
+		} else {
+			where.WriteString(Attribute(c[i].Attribute))
+			where.WriteString(" ")
+			where.WriteString(Operator(c[i].Operator))
+			where.WriteString(" ?")
+			args = append(args, c[i].Value)
+		}
 
 		if i != t {
 			where.WriteString(" AND ")
 		}
-
-		args[i] = c[i].Value
 	}
 
 	return where.String(), args
 }
 
+// inList returns the list of values of an IN / NOT IN condition whose value is a slice
+func inList(c repository.Condition) ([]interface{}, bool) {
+	if c.Operator != repository.In && c.Operator != repository.NotIn {
+		return nil, false
+	}
+
+	if _, ok := c.Value.([]byte); ok {
+		return nil, false
+	}
+
+	var v = reflect.ValueOf(c.Value)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	var list = make([]interface{}, v.Len())
+	for i := range list {
+		list[i] = v.Index(i).Interface()
+	}
+
+	return list, true
+}
+
+// inToSQL returns `attr` IN (?,?,...) with n placeholders; an empty list matches nothing for IN and everything for NOT IN
+func inToSQL(c repository.Condition, n int) string {
+	if n == 0 && c.Operator == repository.In {
+		return "1 = 0"
+	} else if n == 0 {
+		return "1 = 1"
+	}
+
+	return Attribute(c.Attribute) + " " + Operator(c.Operator) + " (?" + strings.Repeat(",?", n-1) + ")"
+}
+
+// Pattern returns the LIKE expression and argument of a pattern condition. Values of Contains, StartsWith and EndsWith
+// are escaped to be matched literally and compared case-sensitively; their I variants compare lowercase strings. Like
+// values are LIKE patterns and are used as is.
+func Pattern(c repository.Condition) (string, interface{}) {
+	var (
+		attr = Attribute(c.Attribute)
+		v    = patternValue(c.Value)
+	)
+
+	if c.Operator == repository.Like {
+		return attr + " LIKE ?", v
+	}
+
+	var expr = attr + " LIKE ? COLLATE utf8mb4_bin"
+	if c.Operator.IgnoresCase() {
+		expr = "LOWER(" + attr + ") LIKE ?"
+		v = strings.ToLower(v)
+	}
+
+	v = LikeEscape(v)
+
+	switch c.Operator {
+	case repository.Contains, repository.IContains:
+		v = "%" + v + "%"
+	case repository.StartsWith, repository.IStartsWith:
+		v = v + "%"
+	case repository.EndsWith, repository.IEndsWith:
+		v = "%" + v
+	}
+
+	return expr, v
+}
+
+// LikeEscape escapes the wildcards (% and _) and escape character (\) of LIKE patterns, so that s is matched literally
+func LikeEscape(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// patternValue returns the string of a pattern condition value
+func patternValue(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case *string:
+		if s != nil {
+			return *s
+		}
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// Attribute returns a quoted column name; attributes of the form field.path.to.key are treated as paths within a json
+// column and use the ->> (JSON_UNQUOTE(JSON_EXTRACT(...))) operator
+func Attribute(attr string) string {
+	var name, path = repository.SplitPath(attr)
+	if len(path) == 0 {
+		return "`" + name + "`"
+	}
+
+	var b strings.Builder
+	b.WriteString("`")
+	b.WriteString(name)
+	b.WriteString("`->>'$")
+
+	for _, seg := range path {
+		b.WriteString(".")
+		if repository.ValidPath([]string{seg}) {
+			b.WriteString(seg)
+			continue
+		}
+
+		// quote anything unusual as a json path key
+		seg = strings.ReplaceAll(seg, `\`, `\\`)
+		seg = strings.ReplaceAll(seg, `"`, `\"`)
+		seg = strings.ReplaceAll(seg, `'`, `''`)
+		b.WriteString(`"` + seg + `"`)
+	}
+
+	b.WriteString("'")
+
+	return b.String()
+}
+
+// encodeJSON returns values whose json fields, if named is an entity, are encoded as json whatever their go type:
+// strings, numbers and typed maps or slices are valid json values but would not be passed as such to the driver
+func encodeJSON(named repository.Named, vals *values.Values) *values.Values {
+	var e, ok = named.(entity.Entity)
+	if !ok {
+		return vals
+	}
+
+	var (
+		f       = e.Fields()
+		encoded *values.Values
+		it      = vals.Iterator()
+	)
+
+	for it.Next() {
+		var v = it.Value()
+		if v.Value == nil || f.TypeOf(v.Name) != types.JSON {
+			continue
+		}
+
+		if encoded == nil {
+			encoded = vals.Clone()
+		}
+
+		if b, err := json.Marshal(v.Value); err == nil {
+			encoded.Set(v.Name, string(b))
+		}
+	}
+
+	if encoded == nil {
+		return vals
+	}
+
+	return encoded
+}
+
+// Arg converts a value into a mysql compatible argument; decoded json structures are encoded back into json
+func Arg(v interface{}) interface{} {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}, json.RawMessage:
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+	}
+
+	return v
+}
+
 func PaginationToOrderBy(p repository.Pagination) string {
 	var (
 		b strings.Builder
@@ -200,6 +382,70 @@ func List(entity entity.Entity, p repository.Pagination, c ...repository.Conditi
 	return q
 }
 
+// Count generates Query for a SELECT COUNT(*) operation (based on 0 or more conditions)
+func Count(named repository.Named, c ...repository.Condition) Query {
+	var name = named.Name()
+	if name == "" {
+		return Query{}
+	}
+
+	var where, args = ConditionsToWhere(c)
+
+	return Query{
+		SQL:  "SELECT COUNT(*) FROM `" + name + "`" + where,
+		Args: args,
+	}
+}
+
+// Match returns the MATCH ... AGAINST expression of full-text search on fields; a FULLTEXT index on exactly these
+// fields is required
+func Match(fields []string) string {
+	var c = make([]string, len(fields))
+	for i := range fields {
+		c[i] = "`" + fields[i] + "`"
+	}
+
+	return "MATCH (" + strings.Join(c, ",") + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+}
+
+// Search generates Query for a SELECT operation matching terms on the search fields of an entity, most relevant first
+func Search(e entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) Query {
+	var (
+		name   = e.Name()
+		f      = e.Fields()
+		search = entity.SearchFieldsOf(e)
+		q      = Query{}
+	)
+
+	if name == "" || f.IsEmpty() || len(search) == 0 {
+		return q
+	}
+
+	var (
+		match      = Match(search)
+		where, arg = ConditionsToWhere(c)
+		order      = " ORDER BY " + match + " DESC"
+		pagination = PaginationToOrderBy(p)
+	)
+
+	if where == "" {
+		where = " WHERE " + match
+	} else {
+		where = " WHERE " + match + " AND " + strings.TrimPrefix(where, " WHERE ")
+	}
+
+	if strings.HasPrefix(pagination, " ORDER BY ") {
+		order += ", " + strings.TrimPrefix(pagination, " ORDER BY ")
+	} else {
+		order += pagination
+	}
+
+	q.SQL = "SELECT " + SelectFieldNames(f) + " FROM `" + name + "`" + where + order
+	q.Args = append(append([]interface{}{terms}, arg...), terms)
+
+	return q
+}
+
 // Delete generates Query for a DELETE operation (by id)
 func Delete(named repository.Named, id string) Query {
 	var name = named.Name()
@@ -249,12 +495,12 @@ func Create(named repository.Named, vals *values.Values) Query {
 		p = make([]string, n)
 	)
 
-	var it = vals.Iterator()
+	var it = encodeJSON(named, vals).Iterator()
 	for i := 0; it.Next(); i++ {
 		v := it.Value()
 		m[i] = "`" + v.Name + "`"
 		p[i] = "?"
-		q.Args[i] = v.Value
+		q.Args[i] = Arg(v.Value)
 	}
 
 	q.SQL = "INSERT INTO `" + name + "` (" + strings.Join(m, ",") + ") VALUES (" + strings.Join(p, ",") + ")"
@@ -279,7 +525,7 @@ func ValuesToSet(vals *values.Values) (set string, args []interface{}) {
 	for i := 0; it.Next(); i++ {
 		c := it.Value()
 		s[i] = "`" + c.Name + "` = ?"
-		args[i] = c.Value
+		args[i] = Arg(c.Value)
 	}
 
 	return "SET " + strings.Join(s, ", "), args
@@ -293,7 +539,7 @@ func Update(named repository.Named, id string, vals *values.Values) Query {
 		return Query{}
 	}
 
-	var set, args = ValuesToSet(vals)
+	var set, args = ValuesToSet(encodeJSON(named, vals))
 
 	return Query{
 		SQL:  "UPDATE `" + name + "` " + set + " WHERE `id` = ?",
@@ -309,7 +555,7 @@ func UpdateWhere(named repository.Named, vals *values.Values, c ...repository.Co
 		return Query{}
 	}
 
-	var set, args = ValuesToSet(vals)
+	var set, args = ValuesToSet(encodeJSON(named, vals))
 	var where, argw = ConditionsToWhere(c)
 
 	return Query{
@@ -331,3 +577,165 @@ func GetScanDest(f fields.Fields) []interface{} {
 
 	return dst
 }
+
+// ScanDestToValues returns values from memory locations filled by a row scan (see GetScanDest)
+// json fields are decoded into structures
+func ScanDestToValues(f fields.Fields, dst []interface{}) (values.Values, error) {
+	var (
+		vals values.Values
+		it   = f.Iterator()
+	)
+
+	for i := 0; it.Next(); i++ {
+		var field = it.Field()
+
+		if field.Kind != types.JSON {
+			vals.Set(field.Name, dst[i])
+			continue
+		}
+
+		var b, _ = dst[i].(*[]byte)
+		if b == nil {
+			vals.Set(field.Name, nil)
+			continue
+		}
+
+		var v, err = types.JSONFromBytes(*b)
+		if err != nil {
+			return vals, err
+		}
+
+		vals.Set(field.Name, v)
+	}
+
+	return vals, nil
+}
+
+// AggregateFunc returns the mysql expression of an aggregate, example SUM(`price`)
+func AggregateFunc(a repository.Aggregate) string {
+	var attr = "*"
+	if a.Attribute != "" {
+		attr = Attribute(a.Attribute)
+	}
+
+	switch a.Func {
+	case repository.Count:
+		return "COUNT(" + attr + ")"
+	case repository.Sum:
+		return "SUM(" + attr + ")"
+	case repository.Avg:
+		return "AVG(" + attr + ")"
+	case repository.Min:
+		return "MIN(" + attr + ")"
+	case repository.Max:
+		return "MAX(" + attr + ")"
+	}
+
+	return ""
+}
+
+// Aggregate generates Query for a SELECT ... GROUP BY operation
+func Aggregate(named repository.Named, a repository.Aggregation) Query {
+	var name = named.Name()
+	if name == "" || len(a.Aggregates) == 0 {
+		return Query{}
+	}
+
+	var (
+		q      Query
+		where  string
+		having string
+		args   []interface{}
+		s      = make([]string, 0, len(a.GroupBy)+len(a.Aggregates))
+		g      = make([]string, len(a.GroupBy))
+	)
+
+	for i := range a.GroupBy {
+		g[i] = Attribute(a.GroupBy[i])
+		s = append(s, g[i])
+	}
+
+	for _, agg := range a.Aggregates {
+		s = append(s, AggregateFunc(agg)+" AS `"+agg.Name()+"`")
+	}
+
+	where, q.Args = ConditionsToWhere(a.Conditions)
+	having, args = ConditionsToWhere(a.Having)
+	q.Args = append(q.Args, args...)
+
+	q.SQL = "SELECT " + strings.Join(s, ",") + " FROM `" + name + "`" + where
+
+	if len(g) != 0 {
+		q.SQL += " GROUP BY " + strings.Join(g, ",")
+	}
+
+	if having != "" {
+		q.SQL += " HAVING " + strings.TrimPrefix(having, " WHERE ")
+	}
+
+	q.SQL += PaginationToOrderBy(repository.Pagination{Order: a.Order, Limit: a.Limit})
+
+	return q
+}
+
+// GetAggregateScanDest returns a slice of memory locations appropriate for scanning the rows of an aggregation
+func GetAggregateScanDest(f fields.Fields, a repository.Aggregation) []interface{} {
+	var dst = make([]interface{}, 0, len(a.GroupBy)+len(a.Aggregates))
+
+	for _, attr := range a.GroupBy {
+		dst = append(dst, types.New(f.TypeOf(attr)))
+	}
+
+	for _, agg := range a.Aggregates {
+		switch agg.Func {
+		case repository.Count:
+			dst = append(dst, new(int64))
+		case repository.Sum, repository.Avg:
+			dst = append(dst, new(sql.NullFloat64))
+		default:
+			dst = append(dst, nullable(f.TypeOf(agg.Attribute)))
+		}
+	}
+
+	return dst
+}
+
+// nullable returns a memory location for scanning a value of a type which may be NULL, as MIN or MAX of no rows are
+func nullable(t types.Type) interface{} {
+	switch t {
+	case types.Bool:
+		return new(sql.NullBool)
+	case types.String:
+		return new(sql.NullString)
+	case types.Int64:
+		return new(sql.NullInt64)
+	case types.Float64:
+		return new(sql.NullFloat64)
+	}
+
+	return types.New(t)
+}
+
+// AggregateScanDestToValues returns values from memory locations filled by a row scan (see GetAggregateScanDest)
+func AggregateScanDestToValues(a repository.Aggregation, dst []interface{}) values.Values {
+	var (
+		vals values.Values
+		n    = len(a.GroupBy)
+	)
+
+	for i, attr := range a.GroupBy {
+		vals.Set(attr, dst[i])
+	}
+
+	for i, agg := range a.Aggregates {
+		var v = dst[n+i]
+
+		if null, ok := v.(driver.Valuer); ok {
+			v, _ = null.Value() // nil if NULL
+		}
+
+		vals.Set(agg.Name(), v)
+	}
+
+	return vals
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
 	"time"
@@ -210,6 +211,39 @@ func TestConditionsToWhere(t *testing.T) {
 			wantSQL:  " WHERE `name` LIKE ?",
 			wantArgs: []interface{}{"%wakanda%"},
 		},
+		{
+			name: "Contains escaped",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "name",
+						Operator:  repository.Contains,
+						Value:     `100%_\`,
+					},
+				},
+			},
+			wantSQL:  " WHERE `name` LIKE ? COLLATE utf8mb4_bin",
+			wantArgs: []interface{}{`%100\%\_\\%`},
+		},
+		{
+			name: "StartsWith, IEndsWith",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "code",
+						Operator:  repository.StartsWith,
+						Value:     "MU",
+					},
+					{
+						Attribute: "email",
+						Operator:  repository.IEndsWith,
+						Value:     "@Example.com",
+					},
+				},
+			},
+			wantSQL:  " WHERE `code` LIKE ? COLLATE utf8mb4_bin AND LOWER(`email`) LIKE ?",
+			wantArgs: []interface{}{"MU%", "%@example.com"},
+		},
 		{
 			name: "Combination LessOrEqualTo, Equals",
 			args: args{
@@ -231,6 +265,78 @@ func TestConditionsToWhere(t *testing.T) {
 			wantSQL:  " WHERE `age` <= ? AND `name` = ?",
 			wantArgs: []interface{}{18, "Foo"},
 		},
+		{
+			name: "JSON path",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "attrs.color.name",
+						Operator:  repository.Equals,
+						Value:     "red",
+						Type:      repository.And,
+					},
+				},
+			},
+			wantSQL:  " WHERE `attrs`->>'$.color.name' = ?",
+			wantArgs: []interface{}{"red"},
+		},
+		{
+			name: "JSON path quoted",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "attrs.it's",
+						Operator:  repository.Equals,
+						Value:     "x",
+						Type:      repository.And,
+					},
+				},
+			},
+			wantSQL:  " WHERE `attrs`->>'$.\"it''s\"' = ?",
+			wantArgs: []interface{}{"x"},
+		},
+		{
+			name: "In list",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "id",
+						Operator:  repository.In,
+						Value:     []interface{}{"a", "b", "c"},
+						Type:      repository.And,
+					},
+					{
+						Attribute: "status",
+						Operator:  repository.NotIn,
+						Value:     []string{"x"},
+						Type:      repository.And,
+					},
+				},
+			},
+			wantSQL:  " WHERE `id` IN (?,?,?) AND `status` NOT IN (?)",
+			wantArgs: []interface{}{"a", "b", "c", "x"},
+		},
+		{
+			name: "In empty list",
+			args: args{
+				c: []repository.Condition{
+					{
+						Attribute: "id",
+						Operator:  repository.In,
+						Value:     []interface{}{},
+						Type:      repository.And,
+					},
+					{
+						Attribute: "id",
+						Operator:  repository.NotIn,
+						Value:     []interface{}{},
+						Type:      repository.And,
+					},
+				},
+			},
+			wantSQL:  " WHERE 1 = 0 AND 1 = 1",
+			wantArgs: []interface{}{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -348,6 +454,28 @@ func TestCreate(t *testing.T) {
 				Args: []interface{}{"Apple", "red", 3.5},
 			},
 		},
+		{
+			name: "JSON fields",
+			args: args{
+				named: ent{
+					name: "tenants",
+					fields: fields.From(
+						fields.Field{Name: "name", Kind: types.String},
+						fields.Field{Name: "quotas", Kind: types.JSON},
+						fields.Field{Name: "label", Kind: types.JSON},
+					),
+				},
+				vals: values.FromSlice([]values.Value{
+					{Name: "name", Value: "acme"},
+					{Name: "quotas", Value: map[string]int64{"users": 10}},
+					{Name: "label", Value: "gold"},
+				}),
+			},
+			want: Query{
+				SQL:  "INSERT INTO `tenants` (`name`,`quotas`,`label`) VALUES (?,?,?)",
+				Args: []interface{}{"acme", `{"users":10}`, `"gold"`},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1399,3 +1527,168 @@ func TestValuesToSet(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name string
+		a    repository.Aggregation
+		want Query
+	}{
+		{
+			name: "No aggregates",
+			a:    repository.Aggregation{GroupBy: []string{"status"}},
+			want: Query{},
+		},
+		{
+			name: "Count all",
+			a: repository.Aggregation{
+				Aggregates: []repository.Aggregate{{Func: repository.Count}},
+			},
+			want: Query{SQL: "SELECT COUNT(*) AS `count` FROM `orders`"},
+		},
+		{
+			name: "Complete",
+			a: repository.Aggregation{
+				GroupBy: []string{"status", "country"},
+				Aggregates: []repository.Aggregate{
+					{Func: repository.Count},
+					{Func: repository.Avg, Attribute: "total", Alias: "average"},
+				},
+				Conditions: []repository.Condition{{Attribute: "active", Operator: repository.Equals, Value: true}},
+				Having:     []repository.Condition{{Attribute: "count", Operator: repository.GreaterThan, Value: float64(5)}},
+				Order:      []repository.OrderBy{{Attribute: "average", Sort: repository.Descending}},
+				Limit:      10,
+			},
+			want: Query{
+				SQL: "SELECT `status`,`country`,COUNT(*) AS `count`,AVG(`total`) AS `average` FROM `orders`" +
+					" WHERE `active` = ? GROUP BY `status`,`country` HAVING `count` > ? ORDER BY `average` DESC LIMIT 10",
+				Args: []interface{}{true, float64(5)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compareQueries(t, Aggregate(named{name: "orders"}, tt.a), tt.want)
+		})
+	}
+}
+
+func TestAggregateScanDestToValues(t *testing.T) {
+	var (
+		f = fields.From(fields.Field{Name: "status", Kind: types.String}, fields.Field{Name: "total", Kind: types.Int64})
+		a = repository.Aggregation{
+			GroupBy: []string{"status"},
+			Aggregates: []repository.Aggregate{
+				{Func: repository.Count},
+				{Func: repository.Min, Attribute: "total"},
+				{Func: repository.Max, Attribute: "total"},
+				{Func: repository.Sum, Attribute: "total"},
+			},
+		}
+		dst = GetAggregateScanDest(f, a)
+	)
+
+	*dst[0].(*string) = "new"
+	*dst[1].(*int64) = 3
+	dst[3].(*sql.NullInt64).Scan(int64(12))
+	dst[4].(*sql.NullFloat64).Scan(float64(12))
+
+	var want = map[string]interface{}{
+		"status":    dst[0],
+		"count":     dst[1],
+		"min_total": nil, // no rows with a total
+		"max_total": int64(12),
+		"sum_total": float64(12),
+	}
+
+	var got = AggregateScanDestToValues(a, dst)
+	for k, w := range want {
+		if v := got.Get(k); v == nil || !reflect.DeepEqual(v.Value, w) {
+			t.Errorf("AggregateScanDestToValues() %s = %v, want %v", k, v, w)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var e = entity.Spec{
+		EntityName:  "article",
+		FieldSpecs:  []entity.FieldSpec{{Name: "id", Kind: types.String}, {Name: "title", Kind: types.String}, {Name: "body", Kind: types.String}},
+		SearchSpecs: []string{"title", "body"},
+	}
+
+	tests := []struct {
+		name string
+		e    entity.Entity
+		p    repository.Pagination
+		c    []repository.Condition
+		want Query
+	}{
+		{
+			name: "Not searchable",
+			e:    ent{name: "article", fields: e.Fields()},
+			want: Query{},
+		},
+		{
+			name: "Terms only",
+			e:    e,
+			want: Query{
+				SQL: "SELECT `id`,`title`,`body` FROM `article` WHERE MATCH (`title`,`body`) AGAINST (? IN NATURAL LANGUAGE MODE)" +
+					" ORDER BY MATCH (`title`,`body`) AGAINST (? IN NATURAL LANGUAGE MODE) DESC",
+				Args: []interface{}{"golang", "golang"},
+			},
+		},
+		{
+			name: "Conditions and pagination",
+			e:    e,
+			p:    repository.Pagination{Order: []repository.OrderBy{{Attribute: "title"}}, Limit: 5},
+			c:    []repository.Condition{{Attribute: "id", Operator: repository.NotEquals, Value: "1"}},
+			want: Query{
+				SQL: "SELECT `id`,`title`,`body` FROM `article` WHERE MATCH (`title`,`body`) AGAINST (? IN NATURAL LANGUAGE MODE)" +
+					" AND `id` <> ? ORDER BY MATCH (`title`,`body`) AGAINST (? IN NATURAL LANGUAGE MODE) DESC, `title` ASC LIMIT 5",
+				Args: []interface{}{"golang", "1", "golang"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compareQueries(t, Search(tt.e, "golang", tt.p, tt.c...), tt.want)
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		named repository.Named
+		c     []repository.Condition
+		want  Query
+	}{
+		{
+			name:  "No named",
+			named: named{name: ""},
+			want:  Query{},
+		},
+		{
+			name:  "No conditions",
+			named: named{name: "products"},
+			want:  Query{SQL: "SELECT COUNT(*) FROM `products`"},
+		},
+		{
+			name:  "Conditions",
+			named: named{name: "persons"},
+			c:     []repository.Condition{{Attribute: "age", Operator: repository.LessThan, Value: 18}},
+			want: Query{
+				SQL:  "SELECT COUNT(*) FROM `persons` WHERE `age` < ?",
+				Args: []interface{}{18},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compareQueries(t, Count(tt.named, tt.c...), tt.want)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository/outbox"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ outbox.Store = &Outbox{}
+}
+
+// OutboxTable returns the CREATE TABLE statement of an outbox table
+func OutboxTable(name string) string {
+	return "CREATE TABLE IF NOT EXISTS `" + name + "` (\n" +
+		"  `seq` BIGINT NOT NULL AUTO_INCREMENT,\n" +
+		"  `entity` VARCHAR(255) NOT NULL,\n" +
+		"  `entity_id` VARCHAR(255) NOT NULL,\n" +
+		"  `type` VARCHAR(16) NOT NULL,\n" +
+		"  `payload` JSON NULL,\n" +
+		"  `created_at` DATETIME(6) NOT NULL,\n" +
+		"  `attempts` INT NOT NULL DEFAULT 0,\n" +
+		"  `next_attempt` DATETIME(6) NOT NULL,\n" +
+		"  PRIMARY KEY (`seq`),\n" +
+		"  KEY `aggregate` (`entity`,`entity_id`,`seq`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;"
+}
+
+// OutboxInsert generates Query for an INSERT of an event in an outbox table
+func OutboxInsert(table string, e outbox.Event) Query {
+	var payload interface{}
+	if len(e.Payload) != 0 {
+		payload = string(e.Payload)
+	}
+
+	return Query{
+		SQL: "INSERT INTO `" + table + "` (`entity`, `entity_id`, `type`, `payload`, `created_at`, `next_attempt`)" +
+			" VALUES (?, ?, ?, ?, ?, ?)",
+		Args: []interface{}{e.Entity, e.EntityID, string(e.Type), payload, e.CreatedAt, e.CreatedAt},
+	}
+}
+
+// OutboxPending generates Query for a SELECT of events due at a time, oldest first, excluding events preceded by another
+// of the same aggregate
+func OutboxPending(table string, now time.Time, limit int) Query {
+	return Query{
+		SQL: "SELECT o.`seq`, o.`entity`, o.`entity_id`, o.`type`, o.`payload`, o.`created_at`, o.`attempts`" +
+			" FROM `" + table + "` o WHERE o.`next_attempt` <= ? AND NOT EXISTS (SELECT 1 FROM `" + table + "` p" +
+			" WHERE p.`entity` = o.`entity` AND p.`entity_id` = o.`entity_id` AND p.`seq` < o.`seq`)" +
+			" ORDER BY o.`seq` LIMIT ?",
+		Args: []interface{}{now, limit},
+	}
+}
+
+// Outbox is an outbox.Store on a mysql table (see OutboxTable); events are written by a Repo using the same table (see
+// Repo.WithOutbox). The connection must parse times (parseTime=true).
+type Outbox struct {
+	db    *sql.DB
+	table string
+}
+
+// NewOutbox store on a table
+func NewOutbox(db *sql.DB, table string) *Outbox {
+	return &Outbox{db: db, table: table}
+}
+
+// Pending events due for delivery
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]outbox.Event, error) {
+	var (
+		events []outbox.Event
+		q      = OutboxPending(o.table, time.Now().UTC(), limit)
+	)
+
+	var rs, err = o.db.QueryContext(ctx, q.SQL, q.Args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for err == nil && rs.Next() {
+		var (
+			e       outbox.Event
+			typ     string
+			payload sql.NullString
+		)
+
+		if err = rs.Scan(&e.Seq, &e.Entity, &e.EntityID, &typ, &payload, &e.CreatedAt, &e.Attempts); err != nil {
+			break
+		}
+
+		e.Type = outbox.Type(typ)
+		if payload.Valid {
+			e.Payload = []byte(payload.String)
+		}
+
+		events = append(events, e)
+	}
+
+	if err == nil {
+		err = rs.Err()
+	}
+
+	if cerr := rs.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Delivered removes an event
+func (o *Outbox) Delivered(ctx context.Context, seq int64) error {
+	var _, err = o.db.ExecContext(ctx, "DELETE FROM `"+o.table+"` WHERE `seq` = ?", seq)
+	return err
+}
+
+// Failed records a failed attempt of delivery and schedules the next one
+func (o *Outbox) Failed(ctx context.Context, seq int64, next time.Time) error {
+	var _, err = o.db.ExecContext(
+		ctx,
+		"UPDATE `"+o.table+"` SET `attempts` = `attempts` + 1, `next_attempt` = ? WHERE `seq` = ?",
+		next.UTC(),
+		seq,
+	)
+
+	return err
+}
+
+// WithOutbox returns a repository recording an event in an outbox table (see OutboxTable) in the same transaction as
+// each Create, Update and Delete; UpdateWhere and DeleteWhere record no event
+func (r Repo) WithOutbox(table string) Repo {
+	r.outbox = table
+	return r
+}
+
+// write executes a write query on the primary; if an outbox is set, an event is recorded in the same transaction unless
+// no row was affected
+func (r *Repo) write(ctx context.Context, q Query, typ outbox.Type, name, id string, vals *values.Values) (sql.Result, error) {
+	if r.outbox == "" {
+		return r.db.ExecContext(ctx, q.SQL, q.Args...)
+	}
+
+	var tx, err = r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res sql.Result
+		n   int64 = 1
+	)
+
+	res, err = tx.ExecContext(ctx, q.SQL, q.Args...)
+	if err == nil && typ != outbox.Created {
+		n, err = res.RowsAffected()
+	}
+
+	if err == nil && n != 0 {
+		var e outbox.Event
+		e, err = outbox.NewEvent(name, id, typ, vals)
+
+		if err == nil {
+			var oq = OutboxInsert(r.outbox, e)
+			_, err = tx.ExecContext(ctx, oq.SQL, oq.Args...)
+		}
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return res, tx.Commit()
+}
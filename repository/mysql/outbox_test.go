@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository/outbox"
+	"github.com/fluxynet/gocipe/repository/sqltest"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func TestOutboxInsert(t *testing.T) {
+	var (
+		now = time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+		e   = outbox.Event{Entity: "order", EntityID: "1", Type: outbox.Created, Payload: []byte(`{"id":"1"}`), CreatedAt: now}
+	)
+
+	compareQueries(t, OutboxInsert("outbox", e), Query{
+		SQL:  "INSERT INTO `outbox` (`entity`, `entity_id`, `type`, `payload`, `created_at`, `next_attempt`) VALUES (?, ?, ?, ?, ?, ?)",
+		Args: []interface{}{"order", "1", "created", `{"id":"1"}`, now, now},
+	})
+
+	e.Payload = nil
+	if q := OutboxInsert("outbox", e); q.Args[3] != nil {
+		t.Errorf("payload of event without values = %v, want NULL", q.Args[3])
+	}
+}
+
+func TestRepo_WithOutbox(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		db   = &sqltest.DB{}
+		repo = New(db.Open(t)).WithOutbox("outbox")
+		e    = entity.ID("order")
+	)
+
+	defer repo.Close()
+
+	var want = func(statements, commits int) {
+		t.Helper()
+
+		if db.Statements() != statements || db.Commits() != commits {
+			t.Errorf("statements = %d, commits = %d, want %d, %d", db.Statements(), db.Commits(), statements, commits)
+		}
+	}
+
+	repo.Create(ctx, e, values.FromMap(map[string]interface{}{"id": "1"}))
+	want(2, 1)
+
+	repo.Update(ctx, e, "1", values.FromMap(map[string]interface{}{"total": 10}))
+	repo.Delete(ctx, e, "1")
+	want(6, 3)
+
+	repo.DeleteWhere(ctx, e)
+	want(7, 3)
+}
@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+)
+
+// primaryKey is the context key forcing reads on the primary
+type primaryKey struct{}
+
+// ReadPrimary returns a context whose reads are routed to the primary instead of replicas; typically used right after a
+// write so that subsequent reads see it despite replication lag (read-your-writes)
+func ReadPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// readsPrimary checks if reads must be routed to the primary
+func readsPrimary(ctx context.Context) bool {
+	var v, _ = ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// Replicas is a pool of read replicas used in turn (round-robin); replicas failing health checks are skipped until they
+// pass again
+type Replicas struct {
+	dbs     []*sql.DB
+	healthy []int32
+	next    uint32
+}
+
+// NewReplicas returns a pool of replicas, all assumed healthy until checked
+func NewReplicas(dbs ...*sql.DB) *Replicas {
+	var p = Replicas{
+		dbs:     dbs,
+		healthy: make([]int32, len(dbs)),
+	}
+
+	for i := range p.healthy {
+		p.healthy[i] = 1
+	}
+
+	return &p
+}
+
+// Next healthy replica in turn; nil if there is none
+func (p *Replicas) Next() *sql.DB {
+	var n = uint32(len(p.dbs))
+
+	for i := uint32(0); i < n; i++ {
+		var k = (atomic.AddUint32(&p.next, 1) - 1) % n
+		if atomic.LoadInt32(&p.healthy[k]) == 1 {
+			return p.dbs[k]
+		}
+	}
+
+	return nil
+}
+
+// Check the health of replicas by pinging them
+func (p *Replicas) Check(ctx context.Context) {
+	for i := range p.dbs {
+		var h int32
+		if p.dbs[i].PingContext(ctx) == nil {
+			h = 1
+		}
+
+		atomic.StoreInt32(&p.healthy[i], h)
+	}
+}
+
+// Watch checks the health of replicas at interval until the context is done; meant to be run in a goroutine
+func (p *Replicas) Watch(ctx context.Context, interval time.Duration) {
+	var t = time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			var c, cancel = context.WithTimeout(ctx, interval)
+			p.Check(c)
+			cancel()
+		}
+	}
+}
+
+// Close connections to replicas; returns the first error encountered
+func (p *Replicas) Close() error {
+	var err error
+
+	for i := range p.dbs {
+		if e := p.dbs[i].Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
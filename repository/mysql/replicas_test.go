@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/sqltest"
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// counting is a stand-in database answering counts with 0
+func counting() *sqltest.DB {
+	return &sqltest.DB{Query: func(query string, args []driver.Value) (*sqltest.Rows, error) {
+		if strings.HasPrefix(query, "SELECT COUNT(*)") {
+			return &sqltest.Rows{Values: [][]driver.Value{{int64(0)}}}, nil
+		}
+
+		return nil, nil
+	}}
+}
+
+func TestReplicated(t *testing.T) {
+	var (
+		ctx             = context.Background()
+		primary, r1, r2 = counting(), counting(), counting()
+
+		pool = NewReplicas(r1.Open(t), r2.Open(t))
+		repo = NewReplicated(primary.Open(t), pool)
+		e    = entity.Spec{EntityName: "country", FieldSpecs: []entity.FieldSpec{{Name: "name", Kind: types.String}}}
+	)
+
+	defer repo.Close()
+
+	var want = func(p, s1, s2 int) {
+		t.Helper()
+
+		if primary.Statements() != p || r1.Statements() != s1 || r2.Statements() != s2 {
+			t.Errorf("statements = primary %d, r1 %d, r2 %d, want %d, %d, %d", primary.Statements(), r1.Statements(), r2.Statements(), p, s1, s2)
+		}
+	}
+
+	repo.Get(ctx, e, "mu")
+	repo.Get(ctx, e, "mu")
+	repo.List(ctx, e, repository.Pagination{})
+	if _, err := repo.Count(ctx, e); err != nil {
+		t.Errorf("Count() error = %v", err)
+	}
+	want(0, 2, 2)
+
+	repo.Create(ctx, e, values.FromMap(map[string]interface{}{"name": "Mauritius"}))
+	repo.Get(ReadPrimary(ctx), e, "mu")
+	want(2, 2, 2)
+
+	r2.SetDown(true)
+
+	pool.Check(ctx)
+	repo.Get(ctx, e, "mu")
+	repo.Get(ctx, e, "mu")
+	want(2, 4, 2)
+
+	r1.SetDown(true)
+
+	pool.Check(ctx)
+	repo.Get(ctx, e, "mu")
+	want(3, 4, 2)
+
+	r1.SetDown(false)
+	r2.SetDown(false)
+
+	pool.Check(ctx)
+	repo.Get(ctx, e, "mu")
+	repo.Get(ctx, e, "mu")
+	want(3, 5, 3)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/outbox"
+	"github.com/fluxynet/gocipe/types/fields"
 	"github.com/fluxynet/gocipe/types/fields/entity"
 	"github.com/fluxynet/gocipe/util"
 	"github.com/fluxynet/gocipe/values"
@@ -14,15 +16,50 @@ import (
 
 func init() {
 	var _ repository.Repositorium = &Repo{}
+	var _ repository.Aggregator = &Repo{}
+	var _ repository.Searcher = &Repo{}
+	var _ repository.Streamer = &Repo{}
+	var _ repository.Counter = &Repo{}
 }
 
 // EntityRepo is an implementation of EntityRepository to allow persistence of Name
 type Repo struct {
-	db *sql.DB
+	db       *sql.DB
+	replicas *Replicas
+	outbox   string
 }
 
 func New(db *sql.DB) Repo {
-	return Repo{db}
+	return Repo{db: db}
+}
+
+// Open a repository on a mysql data source name; the mysql driver must be registered
+func Open(dsn string) (repository.Repositorium, error) {
+	var db, err = sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var r = New(db)
+
+	return &r, nil
+}
+
+// NewReplicated returns a repository writing to the primary and reading from replicas, or from the primary when no
+// replica is healthy or the context requires it (see ReadPrimary)
+func NewReplicated(primary *sql.DB, replicas *Replicas) Repo {
+	return Repo{db: primary, replicas: replicas}
+}
+
+// reader returns the database to read from
+func (r *Repo) reader(ctx context.Context) *sql.DB {
+	if r.replicas != nil && !readsPrimary(ctx) {
+		if db := r.replicas.Next(); db != nil {
+			return db
+		}
+	}
+
+	return r.db
 }
 
 // Get a single Name by id
@@ -31,75 +68,85 @@ func (r *Repo) Get(ctx context.Context, entity entity.Entity, id string) (*value
 		vals values.Values
 		q    = Get(entity, id)
 		f    = entity.Fields()
-		it   = f.Iterator()
 		dst  = GetScanDest(f)
 	)
 
-	var rs, err = r.db.QueryContext(ctx, q.SQL, q.Args...)
-	defer util.Closed(rs, &err)
-
-	if err == sql.ErrNoRows {
-		err = repository.ErrNotFound
-	}
-
+	var rs, err = r.reader(ctx).QueryContext(ctx, q.SQL, q.Args...)
 	if err != nil {
 		return nil, err
 	}
 
-	for rs.Next() {
+	if rs.Next() {
 		err = rs.Scan(dst...)
-		if err != nil {
-			return nil, err
+		if err == nil {
+			vals, err = ScanDestToValues(f, dst)
 		}
+	} else if err = rs.Err(); err == nil {
+		err = repository.ErrNotFound
+	}
 
-		for i := 0; it.Next(); i++ {
-			vals.Set(it.Field().Name, dst[i])
-		}
+	if cerr := rs.Close(); err == nil {
+		err = cerr
 	}
 
-	return &vals, err
+	if err != nil {
+		return nil, err
+	}
+
+	return &vals, nil
 }
 
 // List multiple Name with pagination rules and conditions
 func (r *Repo) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
-	var (
-		l []values.Values
-		q = List(entity, p, c...)
-		f = entity.Fields()
-	)
+	return r.query(ctx, entity.Fields(), List(entity, p, c...))
+}
 
-	var rs, err = r.db.QueryContext(ctx, q.SQL, q.Args...)
-	defer util.Closed(rs, &err)
+// Stream multiple Name with pagination rules and conditions, one row at a time
+func (r *Repo) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	return r.stream(ctx, entity.Fields(), List(entity, p, c...))
+}
 
+// stream returns a cursor over the rows of a select query
+func (r *Repo) stream(ctx context.Context, f fields.Fields, q Query) (values.Cursor, error) {
+	var rs, err = r.reader(ctx).QueryContext(ctx, q.SQL, q.Args...)
 	if err != nil {
 		return nil, err
 	}
 
-	for it := f.Iterator(); rs.Next(); it.Reset() {
-		var (
-			vals values.Values
-			dst  = GetScanDest(f)
-		)
+	return &Cursor{rows: rs, fields: f}, nil
+}
 
-		err = rs.Scan(dst...)
-		if err != nil {
-			return nil, err
-		}
+// query returns the rows of a select query as values
+func (r *Repo) query(ctx context.Context, f fields.Fields, q Query) ([]values.Values, error) {
+	var cur, err = r.stream(ctx, f, q)
+	if err != nil {
+		return nil, err
+	}
 
-		for i := 0; it.Next(); i++ {
-			vals.Set(it.Field().Name, dst[i])
-		}
+	return values.All(cur)
+}
 
-		l = append(l, vals)
-	}
+// Count Name satisfying conditions
+func (r *Repo) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	var (
+		n int64
+		q = Count(entity, c...)
+	)
 
-	return l, err
+	var err = r.reader(ctx).QueryRowContext(ctx, q.SQL, q.Args...).Scan(&n)
+
+	return n, err
+}
+
+// Search items matching terms on the search fields of an entity, most relevant first
+func (r *Repo) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	return r.query(ctx, entity.Fields(), Search(entity, terms, p, c...))
 }
 
 // Delete a single Name by id
 func (r *Repo) Delete(ctx context.Context, named repository.Named, id string) error {
 	var q = Delete(named, id)
-	var res, err = r.db.ExecContext(ctx, q.SQL, q.Args...)
+	var res, err = r.write(ctx, q, outbox.Deleted, named.Name(), id, nil)
 	var n int64
 
 	if err == nil {
@@ -128,6 +175,8 @@ func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.
 		id  string
 	)
 
+	repository.ApplyDefaults(named, vals)
+
 	if v := vals.Get("id"); v != nil && v.IsString() {
 		id = v.String()
 	} else {
@@ -138,7 +187,7 @@ func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.
 
 	q = Create(named, vals)
 
-	_, err = r.db.ExecContext(ctx, q.SQL, q.Args...)
+	_, err = r.write(ctx, q, outbox.Created, named.Name(), id, vals)
 
 	return id, err
 }
@@ -153,7 +202,7 @@ func (r *Repo) Update(ctx context.Context, named repository.Named, id string, va
 		vals,
 	)
 
-	var res, err = r.db.ExecContext(ctx, q.SQL, q.Args...)
+	var res, err = r.write(ctx, q, outbox.Updated, named.Name(), id, vals)
 	var n int64
 
 	if err == nil {
@@ -180,11 +229,50 @@ func (r *Repo) UpdateWhere(ctx context.Context, named repository.Named, vals *va
 	return err
 }
 
-// Close db connection
+// Close db connection, and those of replicas
 func (r *Repo) Close() error {
+	var err error
+
+	if r.replicas != nil {
+		err = r.replicas.Close()
+	}
+
 	if r.db == nil {
-		return nil
+		return err
+	}
+
+	if e := r.db.Close(); e != nil {
+		err = e
 	}
 
-	return r.db.Close()
+	return err
+}
+
+// Aggregate items of an entity grouped by attributes
+func (r *Repo) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	var (
+		l []values.Values
+		q = Aggregate(entity, a)
+		f = entity.Fields()
+	)
+
+	var rs, err = r.reader(ctx).QueryContext(ctx, q.SQL, q.Args...)
+	defer util.Closed(rs, &err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for rs.Next() {
+		var dst = GetAggregateScanDest(f, a)
+
+		err = rs.Scan(dst...)
+		if err != nil {
+			return nil, err
+		}
+
+		l = append(l, AggregateScanDestToValues(a, dst))
+	}
+
+	return l, err
 }
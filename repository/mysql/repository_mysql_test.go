@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func TestRepo_Get(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		repo  = New(counting().Open(t))
+		order = entity.Spec{
+			EntityName:    "order",
+			FieldSpecs:    []entity.FieldSpec{{Name: "id", Kind: types.String}, {Name: "customer_id", Kind: types.String}},
+			RelationSpecs: []entity.RelationSpec{{Name: "customer", Field: "customer_id", Target: "customer"}},
+		}
+	)
+
+	defer repo.Close()
+
+	if _, err := repo.Get(ctx, order, "1"); err != repository.ErrNotFound {
+		t.Errorf("Get() of missing row error = %v, want %v", err, repository.ErrNotFound)
+	}
+
+	var vals = values.FromMap(map[string]interface{}{"customer_id": "dangling"})
+	if err := repository.CheckReferences(ctx, &repo, order, vals); !errors.Is(err, repository.ErrReferenceNotFound) {
+		t.Errorf("CheckReferences() error = %v, want %v", err, repository.ErrReferenceNotFound)
+	}
+}
@@ -0,0 +1,183 @@
+package observed
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/telemetry"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ repository.Repositorium = &Repo{}
+	var _ repository.Aggregator = &Repo{}
+	var _ repository.Searcher = &Repo{}
+	var _ repository.Streamer = &Repo{}
+	var _ repository.Counter = &Repo{}
+}
+
+// Repo is a repository recording telemetry of the operations of another: a span and the latency of each operation per
+// entity, and failures, which are also logged. Items not found are not failures.
+type Repo struct {
+	repo      repository.Repositorium
+	telemetry *telemetry.Telemetry
+}
+
+// New repository recording telemetry of another
+func New(repo repository.Repositorium, t *telemetry.Telemetry) *Repo {
+	return &Repo{repo: repo, telemetry: t}
+}
+
+// observe starts telemetry of an operation; the function returned ends it with the error of the operation
+func (r *Repo) observe(ctx context.Context, op, name string) (context.Context, func(err error)) {
+	var (
+		start      = time.Now()
+		ctx2, span = r.telemetry.Start(
+			ctx,
+			"repository "+op+" "+name,
+			telemetry.Attr("entity", name),
+			telemetry.Attr("op", op),
+		)
+	)
+
+	return ctx2, func(err error) {
+		var elapsed = time.Since(start)
+		r.telemetry.Meter().Observe("repository_operation_duration_seconds", elapsed.Seconds(), "entity", name, "op", op)
+
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			r.telemetry.Meter().Count("repository_operation_errors_total", 1, "entity", name, "op", op)
+			span.RecordError(err)
+			r.telemetry.Log().Error("repository operation failed",
+				"entity", name,
+				"op", op,
+				"duration", elapsed,
+				"error", err,
+			)
+		}
+
+		span.End()
+	}
+}
+
+// Get a single Name by id
+func (r *Repo) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	var ctx2, done = r.observe(ctx, "get", entity.Name())
+
+	var vals, err = r.repo.Get(ctx2, entity, id)
+	done(err)
+
+	return vals, err
+}
+
+// List multiple Name with pagination rules and conditions
+func (r *Repo) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var ctx2, done = r.observe(ctx, "list", entity.Name())
+
+	var list, err = r.repo.List(ctx2, entity, p, c...)
+	done(err)
+
+	return list, err
+}
+
+// Stream multiple Name with pagination rules and conditions; the latency is that of opening the cursor
+func (r *Repo) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	var ctx2, done = r.observe(ctx, "stream", entity.Name())
+
+	var cur, err = repository.Stream(ctx2, r.repo, entity, p, c...)
+	done(err)
+
+	return cur, err
+}
+
+// Search items matching terms
+func (r *Repo) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var ctx2, done = r.observe(ctx, "search", entity.Name())
+
+	var list, err = repository.Search(ctx2, r.repo, entity, terms, p, c...)
+	done(err)
+
+	return list, err
+}
+
+// Count Name satisfying conditions
+func (r *Repo) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	var ctx2, done = r.observe(ctx, "count", entity.Name())
+
+	var n, err = repository.CountOf(ctx2, r.repo, entity, c...)
+	done(err)
+
+	return n, err
+}
+
+// Aggregate items of an entity
+func (r *Repo) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	var agg, ok = r.repo.(repository.Aggregator)
+	if !ok {
+		return nil, repository.ErrNotSupported
+	}
+
+	var ctx2, done = r.observe(ctx, "aggregate", entity.Name())
+
+	var list, err = agg.Aggregate(ctx2, entity, a)
+	done(err)
+
+	return list, err
+}
+
+// Delete a single Name by id
+func (r *Repo) Delete(ctx context.Context, named repository.Named, id string) error {
+	var ctx2, done = r.observe(ctx, "delete", named.Name())
+
+	var err = r.repo.Delete(ctx2, named, id)
+	done(err)
+
+	return err
+}
+
+// DeleteWhere delete multiple Name based on conditions
+func (r *Repo) DeleteWhere(ctx context.Context, named repository.Named, c ...repository.Condition) error {
+	var ctx2, done = r.observe(ctx, "delete_where", named.Name())
+
+	var err = r.repo.DeleteWhere(ctx2, named, c...)
+	done(err)
+
+	return err
+}
+
+// Create a new Name in persistent storage
+func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	var ctx2, done = r.observe(ctx, "create", named.Name())
+
+	var id, err = r.repo.Create(ctx2, named, vals)
+	done(err)
+
+	return id, err
+}
+
+// Update an existing Name in persistent storage
+func (r *Repo) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	var ctx2, done = r.observe(ctx, "update", named.Name())
+
+	var err = r.repo.Update(ctx2, named, id, vals)
+	done(err)
+
+	return err
+}
+
+// UpdateWhere Values in persistent storage
+func (r *Repo) UpdateWhere(ctx context.Context, named repository.Named, vals *values.Values, c ...repository.Condition) error {
+	var ctx2, done = r.observe(ctx, "update_where", named.Name())
+
+	var err = r.repo.UpdateWhere(ctx2, named, vals, c...)
+	done(err)
+
+	return err
+}
+
+// Close connection to the underlying repository
+func (r *Repo) Close() error {
+	return r.repo.Close()
+}
@@ -0,0 +1,68 @@
+package observed
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/telemetry/telemetrytest"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+var errDown = errors.New("database down")
+
+// stub is a repository finding no item and failing deletions
+type stub struct {
+	repository.Repositorium
+}
+
+func (stub) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (stub) Delete(ctx context.Context, named repository.Named, id string) error {
+	return errDown
+}
+
+func TestRepo(t *testing.T) {
+	var (
+		rec   telemetrytest.Recorder
+		ctx   = context.Background()
+		repo  = New(stub{}, rec.Telemetry())
+		order = entity.ID("order")
+	)
+
+	repo.Get(ctx, order, "1")
+	if err := repo.Delete(ctx, order, "1"); err != errDown {
+		t.Errorf("Delete() error = %v, want %v", err, errDown)
+	}
+
+	if _, err := repo.Aggregate(ctx, order, repository.Aggregation{}); err != repository.ErrNotSupported {
+		t.Errorf("Aggregate() error = %v, want %v", err, repository.ErrNotSupported)
+	}
+
+	for _, op := range []string{"get", "delete"} {
+		if o := rec.Observations("repository_operation_duration_seconds", "entity", "order", "op", op); len(o) != 1 {
+			t.Errorf("latency of %s observed %d times, want 1", op, len(o))
+		}
+	}
+
+	if v := rec.Value("repository_operation_errors_total", "entity", "order", "op", "delete"); v != 1 {
+		t.Errorf("failed deletions = %v, want 1", v)
+	}
+
+	if v := rec.Value("repository_operation_errors_total", "entity", "order", "op", "get"); v != 0 {
+		t.Errorf("item not found counted as failure")
+	}
+
+	var spans = rec.Spans()
+	if len(spans) != 2 || spans[0].Name != "repository get order" || spans[0].Err != nil || spans[1].Err != errDown {
+		t.Errorf("spans = %v", spans)
+	}
+
+	if logs := rec.Logs(); len(logs) != 1 || logs[0].Level != "ERROR" || logs[0].Msg != "repository operation failed" {
+		t.Errorf("logged %v", logs)
+	}
+}
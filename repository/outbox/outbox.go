@@ -0,0 +1,207 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/fluxynet/gocipe/telemetry"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// Type of change an event records
+type Type string
+
+const (
+	// Created item
+	Created = Type("created")
+
+	// Updated item; the payload holds the values updated
+	Updated = Type("updated")
+
+	// Deleted item
+	Deleted = Type("deleted")
+)
+
+// Event records a change to an item, the aggregate, identified by entity and id
+type Event struct {
+	// Seq orders events; assigned by the store
+	Seq int64 `json:"seq"`
+
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Type      Type            `json:"type"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	// Attempts of delivery which failed
+	Attempts int `json:"attempts"`
+}
+
+// NewEvent of a change to an item; vals may be nil
+func NewEvent(entity, id string, t Type, vals *values.Values) (Event, error) {
+	var (
+		err error
+		e   = Event{
+			Entity:    entity,
+			EntityID:  id,
+			Type:      t,
+			CreatedAt: time.Now().UTC(),
+		}
+	)
+
+	if vals != nil {
+		e.Payload, err = json.Marshal(vals.ToMap())
+	}
+
+	return e, err
+}
+
+// Store holds events until delivered
+type Store interface {
+	// Pending events due for delivery, oldest first; events preceded by an undelivered event of the same aggregate are
+	// excluded so that events of an aggregate are delivered in order
+	Pending(ctx context.Context, limit int) ([]Event, error)
+
+	// Delivered removes an event
+	Delivered(ctx context.Context, seq int64) error
+
+	// Failed records a failed attempt of delivery and schedules the next one
+	Failed(ctx context.Context, seq int64, next time.Time) error
+}
+
+// Publisher hands events to other services
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Relay delivers events of a store to a publisher at least once: events are removed only once published, and failed
+// deliveries are retried with exponential backoff. A single relay must run per store for events of an aggregate to be
+// delivered in order.
+type Relay struct {
+	Store     Store
+	Publisher Publisher
+
+	// Interval between polls when no event is pending; defaults to 1s
+	Interval time.Duration
+
+	// Batch is the maximum number of events fetched per poll; defaults to 100
+	Batch int
+
+	// Backoff returns the delay before retrying an event which failed attempts times; defaults to ExponentialBackoff
+	Backoff func(attempts int) time.Duration
+
+	// Telemetry logs failed polls; optional
+	Telemetry *telemetry.Telemetry
+
+	now func() time.Time
+}
+
+// ExponentialBackoff doubles the delay from 1s with each attempt, up to 5 minutes
+func ExponentialBackoff(attempts int) time.Duration {
+	var d = time.Second
+	for i := 1; i < attempts && d < 5*time.Minute; i++ {
+		d *= 2
+	}
+
+	if d > 5*time.Minute {
+		d = 5 * time.Minute
+	}
+
+	return d
+}
+
+// Run delivers events until the context is done. Failed polls are logged and retried after Backoff of the number of
+// consecutive failures.
+func (r *Relay) Run(ctx context.Context) error {
+	var (
+		interval = r.Interval
+		backoff  = r.Backoff
+		failures int
+	)
+
+	if interval == 0 {
+		interval = time.Second
+	}
+
+	if backoff == nil {
+		backoff = ExponentialBackoff
+	}
+
+	for {
+		var (
+			wait   = interval
+			n, err = r.Poll(ctx)
+		)
+
+		if err == nil {
+			failures = 0
+		} else if ctx.Err() == nil {
+			failures++
+			wait = backoff(failures)
+			r.Telemetry.Log().Error("outbox poll failed", "error", err, "failures", failures, "retry_in", wait)
+		}
+
+		if err == nil && n != 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Poll delivers a batch of pending events and returns the number delivered
+func (r *Relay) Poll(ctx context.Context) (int, error) {
+	var (
+		batch   = r.Batch
+		backoff = r.Backoff
+		now     = r.now
+	)
+
+	if batch == 0 {
+		batch = 100
+	}
+
+	if backoff == nil {
+		backoff = ExponentialBackoff
+	}
+
+	if now == nil {
+		now = time.Now
+	}
+
+	var events, err = r.Store.Pending(ctx, batch)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		delivered int
+		blocked   = make(map[[2]string]bool)
+	)
+
+	for _, e := range events {
+		var aggregate = [2]string{e.Entity, e.EntityID}
+		if blocked[aggregate] {
+			continue
+		}
+
+		if err = r.Publisher.Publish(ctx, e); err != nil {
+			blocked[aggregate] = true
+			err = r.Store.Failed(ctx, e.Seq, now().Add(backoff(e.Attempts+1)))
+		} else {
+			err = r.Store.Delivered(ctx, e.Seq)
+			delivered++
+		}
+
+		if err != nil {
+			return delivered, err
+		}
+	}
+
+	return delivered, nil
+}
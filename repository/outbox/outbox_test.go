@@ -0,0 +1,257 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxynet/gocipe/telemetry"
+)
+
+// memory is a store of events in memory
+type memory struct {
+	events    []Event
+	delivered []int64
+	retries   map[int64]time.Time
+}
+
+func (m *memory) Pending(ctx context.Context, limit int) ([]Event, error) {
+	var events = append([]Event(nil), m.events...)
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+func (m *memory) Delivered(ctx context.Context, seq int64) error {
+	m.delivered = append(m.delivered, seq)
+
+	for i := range m.events {
+		if m.events[i].Seq == seq {
+			m.events = append(m.events[:i], m.events[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (m *memory) Failed(ctx context.Context, seq int64, next time.Time) error {
+	m.retries[seq] = next
+	return nil
+}
+
+// failing publisher fails events of an item
+type failing struct {
+	id        string
+	published []int64
+}
+
+func (f *failing) Publish(ctx context.Context, e Event) error {
+	if e.EntityID == f.id {
+		return errors.New("unavailable")
+	}
+
+	f.published = append(f.published, e.Seq)
+	return nil
+}
+
+func TestRelay_Poll(t *testing.T) {
+	var (
+		now   = time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+		store = &memory{
+			retries: map[int64]time.Time{},
+			events: []Event{
+				{Seq: 1, Entity: "order", EntityID: "1", Type: Created},
+				{Seq: 2, Entity: "order", EntityID: "2", Type: Created, Attempts: 2},
+				{Seq: 3, Entity: "order", EntityID: "1", Type: Updated},
+				{Seq: 4, Entity: "order", EntityID: "2", Type: Deleted},
+				{Seq: 5, Entity: "user", EntityID: "2", Type: Created},
+			},
+		}
+		pub   = &failing{id: "2"}
+		relay = Relay{Store: store, Publisher: pub, now: func() time.Time { return now }}
+	)
+
+	var n, err = relay.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	if n != 2 || len(pub.published) != 2 || pub.published[0] != 1 || pub.published[1] != 3 {
+		t.Errorf("Poll() delivered %d: %v, want events 1 and 3", n, pub.published)
+	}
+
+	if len(store.retries) != 2 {
+		t.Fatalf("retries = %v, want events 2 and 5 only", store.retries)
+	}
+
+	if next := store.retries[2]; !next.Equal(now.Add(4 * time.Second)) {
+		t.Errorf("retry of event 2 at %v, want after 4s", next)
+	}
+
+	if next := store.retries[5]; !next.Equal(now.Add(time.Second)) {
+		t.Errorf("retry of event 5 at %v, want after 1s", next)
+	}
+
+	if len(store.events) != 3 {
+		t.Errorf("pending events = %v, want failed events kept", store.events)
+	}
+}
+
+// unavailable store fails to list pending events
+type unavailable struct {
+	memory
+}
+
+func (unavailable) Pending(ctx context.Context, limit int) ([]Event, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestRelay_Run(t *testing.T) {
+	var (
+		logs  bytes.Buffer
+		retry []int
+		relay = Relay{
+			Store:     &unavailable{},
+			Telemetry: &telemetry.Telemetry{Logger: telemetry.StdLogger(log.New(&logs, "", 0), true)},
+			Backoff: func(attempts int) time.Duration {
+				retry = append(retry, attempts)
+				return time.Millisecond
+			},
+		}
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := relay.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Run() error = %v, want deadline exceeded", err)
+	}
+
+	if len(retry) < 2 || retry[0] != 1 || retry[1] != 2 {
+		t.Errorf("backoff after failures %v, want increasing from 1", retry)
+	}
+
+	if !strings.Contains(logs.String(), "outbox poll failed") || !strings.Contains(logs.String(), "unavailable") {
+		t.Errorf("logs = %q, want failed polls", logs.String())
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	var tests = []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{9, 256 * time.Second},
+		{10, 5 * time.Minute},
+		{100, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := ExponentialBackoff(tt.attempts); got != tt.want {
+			t.Errorf("ExponentialBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestChannel_Publish(t *testing.T) {
+	var c = make(Channel, 1)
+
+	if err := c.Publish(context.Background(), Event{Seq: 1}); err != nil {
+		t.Errorf("Publish() error = %v", err)
+	}
+
+	if e := <-c; e.Seq != 1 {
+		t.Errorf("received %v, want event 1", e)
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	c <- Event{}
+	if err := c.Publish(ctx, Event{Seq: 2}); err != context.Canceled {
+		t.Errorf("Publish() to full channel error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWebhook_Publish(t *testing.T) {
+	var received Event
+
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+
+		if received.EntityID == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	defer srv.Close()
+
+	var hook = Webhook{URL: srv.URL}
+
+	if err := hook.Publish(context.Background(), Event{Seq: 1, EntityID: "1", Payload: []byte(`{"a":1}`)}); err != nil {
+		t.Errorf("Publish() error = %v", err)
+	}
+
+	if received.Seq != 1 || string(received.Payload) != `{"a":1}` {
+		t.Errorf("received %v", received)
+	}
+
+	if err := hook.Publish(context.Background(), Event{Seq: 2, EntityID: "fail"}); err == nil {
+		t.Errorf("Publish() on status 500 error = nil, want error")
+	}
+}
+
+func TestFile_Publish(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "events.jsonl")
+
+	var f, err = NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.Publish(context.Background(), Event{Seq: 1})
+	f.Publish(context.Background(), Event{Seq: 2})
+	f.Close()
+
+	var r *os.File
+	if r, err = os.Open(path); err != nil {
+		t.Fatal(err)
+	}
+
+	defer r.Close()
+
+	var (
+		seqs    []int64
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		var e Event
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+
+		seqs = append(seqs, e.Seq)
+	}
+
+	if len(seqs) != 2 || seqs[0] != 1 || seqs[1] != 2 {
+		t.Errorf("file holds events %v, want 1 and 2", seqs)
+	}
+}
@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// webhookClient is the default client of webhooks; unlike http.DefaultClient it does not wait forever on a receiver
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// Channel publishes events to an in-process channel; publishing blocks until the event is received or the context is
+// done
+type Channel chan Event
+
+// Publish an event to the channel
+func (c Channel) Publish(ctx context.Context, e Event) error {
+	select {
+	case c <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Webhook publishes events as json POST requests to a URL; responses other than 2xx are failures
+type Webhook struct {
+	URL string
+
+	// Client used for requests; defaults to a client timing out after 10s
+	Client *http.Client
+}
+
+// Publish an event to the webhook
+func (w Webhook) Publish(ctx context.Context, e Event) error {
+	var b, err = json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var client = w.Client
+	if client == nil {
+		client = webhookClient
+	}
+
+	var res *http.Response
+	res, err = client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// File publishes events as json lines appended to a file
+type File struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFile opens a file to append events to, creating it if needed
+func NewFile(path string) (*File, error) {
+	var f, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{file: f}, nil
+}
+
+// Publish an event to the file
+func (f *File) Publish(ctx context.Context, e Event) error {
+	var b, err = json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err = f.file.Write(append(b, '\n'))
+
+	return err
+}
+
+// Close the file
+func (f *File) Close() error {
+	return f.file.Close()
+}
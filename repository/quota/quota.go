@@ -0,0 +1,86 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ repository.Repositorium = &Repo{}
+	var _ repository.Aggregator = &Repo{}
+	var _ repository.Searcher = &Repo{}
+	var _ repository.Streamer = &Repo{}
+	var _ repository.Counter = &Repo{}
+}
+
+// Limit returns the maximum number of records of an entity a tenant may hold; 0 is unlimited
+type Limit func(ctx context.Context, tenant, entity string) (int64, error)
+
+// Repo is a repository limiting the number of records per entity: creates fail with repository.ErrQuotaExceeded once
+// the limit of the tenant of the context is reached. Records are counted in the underlying repository, which should be
+// scoped to the tenant (see tenancy.Repo) for limits to apply per tenant. Concurrent creates may exceed the limit
+// slightly as counting and creating are not atomic.
+type Repo struct {
+	repository.Repositorium
+	limit Limit
+}
+
+// New repository limiting the number of records of another
+func New(repo repository.Repositorium, limit Limit) *Repo {
+	return &Repo{Repositorium: repo, limit: limit}
+}
+
+// Usage returns the number of records of an entity held by the tenant of the context, and its limit (0 if unlimited)
+func (r *Repo) Usage(ctx context.Context, name string) (int64, int64, error) {
+	var limit, err = r.limit(ctx, tenant.Get(ctx), name)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var n int64
+	n, err = repository.CountOf(ctx, r.Repositorium, entity.ID(name))
+
+	return n, limit, err
+}
+
+// Create a new Name in persistent storage, unless the quota of the tenant is reached
+func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	var n, limit, err = r.Usage(ctx, named.Name())
+	if err == nil && limit != 0 && n >= limit {
+		err = repository.ErrQuotaExceeded
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return r.Repositorium.Create(ctx, named, vals)
+}
+
+// Stream multiple Name with pagination rules and conditions
+func (r *Repo) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	return repository.Stream(ctx, r.Repositorium, entity, p, c...)
+}
+
+// Search items matching terms
+func (r *Repo) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	return repository.Search(ctx, r.Repositorium, entity, terms, p, c...)
+}
+
+// Count Name satisfying conditions
+func (r *Repo) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	return repository.CountOf(ctx, r.Repositorium, entity, c...)
+}
+
+// Aggregate items of an entity
+func (r *Repo) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	if agg, ok := r.Repositorium.(repository.Aggregator); ok {
+		return agg.Aggregate(ctx, entity, a)
+	}
+
+	return nil, repository.ErrNotSupported
+}
@@ -0,0 +1,57 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// records is a repository counting records created per entity
+type records struct {
+	repository.Repositorium
+	n map[string]int64
+}
+
+func (r *records) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	return r.n[entity.Name()], nil
+}
+
+func (r *records) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	r.n[named.Name()]++
+	return "", nil
+}
+
+func TestRepo(t *testing.T) {
+	var (
+		ctx  = tenant.Set(context.Background(), "acme")
+		note = entity.ID("note")
+		tag  = entity.ID("tag")
+		r    = New(&records{n: map[string]int64{}}, func(ctx context.Context, tenant, entity string) (int64, error) {
+			if tenant == "acme" && entity == "note" {
+				return 2, nil
+			}
+
+			return 0, nil
+		})
+	)
+
+	for i, want := range []error{nil, nil, repository.ErrQuotaExceeded} {
+		if _, err := r.Create(ctx, note, &values.Values{}); err != want {
+			t.Errorf("Create() #%d error = %v, want %v", i, err, want)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := r.Create(ctx, tag, &values.Values{}); err != nil {
+			t.Errorf("Create() of unlimited entity error = %v", err)
+		}
+	}
+
+	if n, limit, err := r.Usage(ctx, "note"); n != 2 || limit != 2 || err != nil {
+		t.Errorf("Usage() = %d, %d, %v, want 2, 2", n, limit, err)
+	}
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+var (
+	// ErrReferenceNotFound is when a foreign key refers to an item which does not exist
+	ErrReferenceNotFound = errors.New("referenced item not found")
+)
+
+// Include loads items related to records and embeds them under the relation name, using a single batched query.
+// BelongsTo relations embed a single item (or nil), HasMany relations embed a list.
+func Include(ctx context.Context, repo Repositorium, target entity.Entity, rel entity.Relation, records []values.Values) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var (
+		attr = "id"
+		keys []interface{}
+		seen = make(map[string]bool, len(records))
+	)
+
+	if rel.Cardinality == entity.HasMany {
+		attr = rel.Field
+	}
+
+	for i := range records {
+		var k = key(records[i], LocalKey(rel))
+		if k != "" && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+
+	var (
+		related []values.Values
+		err     error
+	)
+
+	if len(keys) != 0 {
+		related, err = repo.List(ctx, target, Pagination{}, Condition{Attribute: attr, Operator: In, Value: keys})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var grouped = make(map[string][]map[string]interface{}, len(related))
+	for i := range related {
+		var k = key(related[i], attr)
+		grouped[k] = append(grouped[k], related[i].ToMap())
+	}
+
+	for i := range records {
+		var items = grouped[key(records[i], LocalKey(rel))]
+
+		if rel.Cardinality == entity.HasMany {
+			if items == nil {
+				items = []map[string]interface{}{}
+			}
+
+			records[i].Set(rel.Name, items)
+		} else if len(items) == 0 {
+			records[i].Set(rel.Name, nil)
+		} else {
+			records[i].Set(rel.Name, items[0])
+		}
+	}
+
+	return nil
+}
+
+// CheckReferences ensures items referred to by BelongsTo relations of an entity exist; an id which is not valid for the
+// repository can not refer to an item either
+func CheckReferences(ctx context.Context, repo Repositorium, e entity.Entity, vals *values.Values) error {
+	for _, rel := range entity.RelationsOf(e) {
+		if rel.Cardinality != entity.BelongsTo {
+			continue
+		}
+
+		var k = key(*vals, rel.Field)
+		if k == "" {
+			continue
+		}
+
+		var _, err = repo.Get(ctx, entity.ID(rel.Target), k)
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalidID) {
+			return fmt.Errorf("%w: %s %s", ErrReferenceNotFound, rel.Target, k)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LocalKey is the attribute of the entity used to match related items
+func LocalKey(rel entity.Relation) string {
+	if rel.Cardinality == entity.HasMany {
+		return "id"
+	}
+
+	return rel.Field
+}
+
+// key returns a string representation of a value used for matching related items; empty if absent
+func key(vals values.Values, name string) string {
+	var v = vals.Get(name)
+	if v == nil {
+		return ""
+	}
+
+	switch x := v.Value.(type) {
+	case string:
+		return x
+	case *string:
+		if x != nil {
+			return *x
+		}
+	case *int64:
+		if x != nil {
+			return strconv.FormatInt(*x, 10)
+		}
+	case fmt.Stringer:
+		return x.String()
+	case nil:
+	default:
+		return fmt.Sprint(x)
+	}
+
+	return ""
+}
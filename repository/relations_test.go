@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// related is a repository of items per entity, counting lists; ids starting with ! are invalid
+type related struct {
+	Repositorium
+	items map[string][]values.Values
+	lists int
+}
+
+func (r *related) Get(ctx context.Context, e entity.Entity, id string) (*values.Values, error) {
+	if id[0] == '!' {
+		return nil, ErrInvalidID
+	}
+
+	var found, _ = r.List(ctx, e, Pagination{}, Condition{Attribute: "id", Value: id})
+	r.lists--
+
+	if len(found) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &found[0], nil
+}
+
+func (r *related) List(ctx context.Context, e entity.Entity, p Pagination, c ...Condition) ([]values.Values, error) {
+	var found []values.Values
+
+	r.lists++
+	for i := range r.items[e.Name()] {
+		if Match(&r.items[e.Name()][i], c...) {
+			found = append(found, *r.items[e.Name()][i].Clone())
+		}
+	}
+
+	return found, nil
+}
+
+func newRelated() *related {
+	return &related{items: map[string][]values.Values{
+		"customer": {
+			*values.FromMap(map[string]interface{}{"id": "c1", "name": "Ada"}),
+			*values.FromMap(map[string]interface{}{"id": "c2", "name": "Bob"}),
+		},
+		"order": {
+			*values.FromMap(map[string]interface{}{"id": "o1", "customer_id": "c1"}),
+			*values.FromMap(map[string]interface{}{"id": "o2", "customer_id": "c1"}),
+			*values.FromMap(map[string]interface{}{"id": "o3", "customer_id": "c3"}),
+		},
+	}}
+}
+
+func TestInclude(t *testing.T) {
+	var (
+		customer = entity.Relation{Name: "customer", Field: "customer_id", Target: "customer", Cardinality: entity.BelongsTo}
+		orders   = entity.Relation{Name: "orders", Field: "customer_id", Target: "order", Cardinality: entity.HasMany}
+	)
+
+	tests := []struct {
+		name    string
+		rel     entity.Relation
+		records string
+		want    map[string]interface{}
+	}{
+		{
+			name:    "Belongs to",
+			rel:     customer,
+			records: "order",
+			want: map[string]interface{}{
+				"o1": map[string]interface{}{"id": "c1", "name": "Ada"},
+				"o2": map[string]interface{}{"id": "c1", "name": "Ada"},
+				"o3": nil,
+			},
+		},
+		{
+			name:    "Has many",
+			rel:     orders,
+			records: "customer",
+			want: map[string]interface{}{
+				"c1": []map[string]interface{}{
+					{"id": "o1", "customer_id": "c1"},
+					{"id": "o2", "customer_id": "c1"},
+				},
+				"c2": []map[string]interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				repo    = newRelated()
+				records = repo.items[tt.records]
+			)
+
+			if err := Include(context.Background(), repo, entity.ID(tt.rel.Target), tt.rel, records); err != nil {
+				t.Fatalf("Include() error = %v", err)
+			}
+
+			if repo.lists != 1 {
+				t.Errorf("Include() listed %d times, want a single batch", repo.lists)
+			}
+
+			for i := range records {
+				var (
+					id  = records[i].Get("id").String()
+					got = records[i].Get(tt.rel.Name)
+				)
+
+				if got == nil || !reflect.DeepEqual(got.Value, tt.want[id]) {
+					t.Errorf("Include() %s of %s = %v, want %v", tt.rel.Name, id, got, tt.want[id])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckReferences(t *testing.T) {
+	var order = entity.Spec{
+		EntityName:    "order",
+		RelationSpecs: []entity.RelationSpec{{Name: "customer", Field: "customer_id", Target: "customer"}},
+	}
+
+	tests := []struct {
+		name     string
+		customer interface{}
+		wantErr  error
+	}{
+		{name: "Existing", customer: "c1"},
+		{name: "Absent", customer: nil},
+		{name: "Missing", customer: "c3", wantErr: ErrReferenceNotFound},
+		{name: "Invalid id", customer: "!c", wantErr: ErrReferenceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var vals = values.FromMap(map[string]interface{}{"id": "o4"})
+			if tt.customer != nil {
+				vals.Set("customer_id", tt.customer)
+			}
+
+			if err := CheckReferences(context.Background(), newRelated(), order, vals); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckReferences() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -16,6 +17,9 @@ var (
 	// ErrNotFound is when the item was not found
 	ErrNotFound = errors.New("item not found")
 
+	// ErrInvalidID when an id is not in the format of the repository
+	ErrInvalidID = errors.New("invalid id")
+
 	// ErrUnknownSortAttribute when an unknown sort attribute is passed
 	ErrUnknownSortAttribute = errors.New("unknown sort attribute")
 
@@ -24,8 +28,40 @@ var (
 
 	// ErrInvalidAttribute when an invalid attribute is passed
 	ErrInvalidAttribute = errors.New("invalid conditional attribute")
+
+	// ErrConflict when an item conflicts with another, typically a unique index violation
+	ErrConflict = errors.New("item conflicts with an existing one")
+
+	// ErrNotSupported when the repository does not support an optional operation, such as Aggregate
+	ErrNotSupported = errors.New("operation not supported")
+
+	// ErrQuotaExceeded when creating an item would exceed the quota of the tenant
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	// ErrInvalid when a request is invalid, example a malformed subscription; errors of packages built on repositories
+	// are declared as kinds of it (see NewError) so that callers report them as errors of the client
+	ErrInvalid = errors.New("invalid request")
 )
 
+// NewError returns an error with a message which is of a kind: errors.Is(err, kind) holds. Packages built on
+// repositories declare their errors as kinds of those of this package, so that callers need not know them all.
+func NewError(kind error, text string) error {
+	return &kindError{kind: kind, text: text}
+}
+
+type kindError struct {
+	kind error
+	text string
+}
+
+func (e *kindError) Error() string {
+	return e.text
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
 // ConditionOperator represents the condition wrt the value
 type ConditionOperator uint8
 
@@ -48,7 +84,8 @@ const (
 	// LessOrEqualTo denotes Less than or Equal to
 	LessOrEqualTo = ConditionOperator(5)
 
-	// Like for strings that match a term
+	// Like for strings that match a LIKE pattern, where % matches any sequence of characters, _ any single character
+	// and \ escapes the next character
 	Like = ConditionOperator(6)
 
 	// In denotes In
@@ -56,8 +93,37 @@ const (
 
 	// NotIn denotes Not in
 	NotIn = ConditionOperator(8)
+
+	// Contains for strings containing the value
+	Contains = ConditionOperator(9)
+
+	// StartsWith for strings starting with the value
+	StartsWith = ConditionOperator(10)
+
+	// EndsWith for strings ending with the value
+	EndsWith = ConditionOperator(11)
+
+	// IContains for strings containing the value, ignoring case
+	IContains = ConditionOperator(12)
+
+	// IStartsWith for strings starting with the value, ignoring case
+	IStartsWith = ConditionOperator(13)
+
+	// IEndsWith for strings ending with the value, ignoring case
+	IEndsWith = ConditionOperator(14)
 )
 
+// IsPattern checks if the operator matches strings against a pattern (Like, Contains, StartsWith, EndsWith and their
+// case-insensitive variants)
+func (c ConditionOperator) IsPattern() bool {
+	return c == Like || (c >= Contains && c <= IEndsWith)
+}
+
+// IgnoresCase checks if the operator is a case-insensitive pattern
+func (c ConditionOperator) IgnoresCase() bool {
+	return c >= IContains && c <= IEndsWith
+}
+
 // String representation (mainly for testing / debugging / logging)m
 func (c ConditionOperator) String() string {
 	switch c {
@@ -79,6 +145,18 @@ func (c ConditionOperator) String() string {
 		return "IN"
 	case NotIn:
 		return "NOT IN"
+	case Contains:
+		return "CONTAINS"
+	case StartsWith:
+		return "STARTS WITH"
+	case EndsWith:
+		return "ENDS WITH"
+	case IContains:
+		return "ICONTAINS"
+	case IStartsWith:
+		return "ISTARTS WITH"
+	case IEndsWith:
+		return "IENDS WITH"
 	}
 
 	return "?? " + strconv.Itoa(int(c))
@@ -121,6 +199,16 @@ func ConditionsFromMap(m map[string][]string, f fields.Fields) ([]Condition, err
 			v, ok = m[i.Name]
 		)
 
+		if i.Kind == types.JSON {
+			var jc, err = jsonConditionsFromMap(m, i.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			conds = append(conds, jc...)
+			continue
+		}
+
 		if !ok {
 			continue
 		}
@@ -133,31 +221,10 @@ func ConditionsFromMap(m map[string][]string, f fields.Fields) ([]Condition, err
 
 		var c = Condition{Attribute: i.Name}
 
-		var w = v[0]
-		if p := strings.Index(w, ":"); p != -1 {
-			var o string
-			o, w = w[0:p], w[p+1:]
-			switch o {
-			case "eq":
-				c.Operator = Equals
-			case "ne":
-				c.Operator = NotEquals
-			case "gt":
-				c.Operator = GreaterThan
-			case "gte":
-				c.Operator = GreaterOrEqualTo
-			case "lt":
-				c.Operator = LessThan
-			case "lte":
-				c.Operator = LessOrEqualTo
-			case "li":
-				if i.Kind != types.String {
-					return nil, ErrInvalidConditionOperator
-				}
-				c.Operator = Like
-			default:
-				return nil, ErrInvalidConditionOperator
-			}
+		var w string
+		c.Operator, w, err = operatorFromString(v[0], i.Kind)
+		if err != nil {
+			return nil, err
 		}
 
 		switch i.Kind {
@@ -184,6 +251,129 @@ func ConditionsFromMap(m map[string][]string, f fields.Fields) ([]Condition, err
 	return conds, err
 }
 
+// patterns are the query prefixes of pattern operators, applicable to strings only
+var patterns = map[string]ConditionOperator{
+	"li":  Like,
+	"co":  Contains,
+	"sw":  StartsWith,
+	"ew":  EndsWith,
+	"ico": IContains,
+	"isw": IStartsWith,
+	"iew": IEndsWith,
+}
+
+// operatorFromString splits a query value of the form "op:value" into its operator and value
+func operatorFromString(w string, kind types.Type) (ConditionOperator, string, error) {
+	var p = strings.Index(w, ":")
+	if p == -1 {
+		return Equals, w, nil
+	}
+
+	var o string
+	o, w = w[0:p], w[p+1:]
+
+	switch o {
+	case "eq":
+		return Equals, w, nil
+	case "ne":
+		return NotEquals, w, nil
+	case "gt":
+		return GreaterThan, w, nil
+	case "gte":
+		return GreaterOrEqualTo, w, nil
+	case "lt":
+		return LessThan, w, nil
+	case "lte":
+		return LessOrEqualTo, w, nil
+	}
+
+	var op, ok = patterns[o]
+	if !ok {
+		return Equals, w, ErrInvalidConditionOperator
+	}
+
+	if kind != types.String && kind != types.JSON {
+		return Equals, w, ErrInvalidConditionOperator
+	}
+
+	return op, w, nil
+}
+
+// jsonConditionsFromMap returns conditions on paths within a json field; keys are of the form field.path.to.key.
+// Values are compared as strings as extracted from the json document, except numbers given to gt, gte, lt and lte
+// which are compared as numbers.
+func jsonConditionsFromMap(m map[string][]string, name string) ([]Condition, error) {
+	var (
+		keys   []string
+		prefix = name + "."
+	)
+
+	for k := range m {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var conds []Condition
+	for _, k := range keys {
+		var v = m[k]
+		if len(v) != 1 {
+			continue // todo deal with multiple values for IN and NOT IN
+		}
+
+		if _, path := SplitPath(k); !ValidPath(path) {
+			return nil, ErrInvalidAttribute
+		}
+
+		var op, w, err = operatorFromString(v[0], types.JSON)
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{} = w
+		switch op {
+		case GreaterThan, GreaterOrEqualTo, LessThan, LessOrEqualTo:
+			if n, err := strconv.ParseFloat(w, 64); err == nil {
+				value = n
+			}
+		}
+
+		conds = append(conds, Condition{Attribute: k, Operator: op, Value: value})
+	}
+
+	return conds, nil
+}
+
+// SplitPath splits an attribute into the field name and the path within the field (for json fields)
+// e.g. "attrs.color.name" gives "attrs" and ["color", "name"]
+func SplitPath(attr string) (string, []string) {
+	var p = strings.Split(attr, ".")
+	return p[0], p[1:]
+}
+
+// ValidPath checks that a path within a json field is not empty and contains only letters, digits and underscores
+func ValidPath(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	for _, seg := range path {
+		if seg == "" {
+			return false
+		}
+
+		for _, r := range seg {
+			if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // OrderSort is the sort order of data fetched
 type OrderSort uint8
 
@@ -277,6 +467,13 @@ type Named interface {
 	Name() string
 }
 
+// ApplyDefaults sets missing values on creation if named is an entity whose fields declare defaults
+func ApplyDefaults(named Named, vals *values.Values) {
+	if e, ok := named.(entity.Entity); ok && vals != nil {
+		vals.ApplyDefaults(e.Fields())
+	}
+}
+
 // Repositorium allows persistence of Name
 type Repositorium interface {
 	// Get a single Name by id
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -482,6 +484,40 @@ func TestConditionsFromMap(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Patterns",
+			args: args{
+				m: map[string][]string{
+					"name":  {"co:.*"},
+					"code":  {"sw:MU"},
+					"email": {"iew:@example.com"},
+				},
+				f: fields.From(
+					fields.Field{Name: "name", Kind: types.String},
+					fields.Field{Name: "code", Kind: types.String},
+					fields.Field{Name: "email", Kind: types.String},
+				),
+			},
+			want: []Condition{
+				{Attribute: "name", Operator: Contains, Value: ".*"},
+				{Attribute: "code", Operator: StartsWith, Value: "MU"},
+				{Attribute: "email", Operator: IEndsWith, Value: "@example.com"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Pattern on number",
+			args: args{
+				m: map[string][]string{
+					"price": {"co:12"},
+				},
+				f: fields.From(
+					fields.Field{Name: "price", Kind: types.Int64},
+				),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "[]Values",
 			args: args{
@@ -552,6 +588,37 @@ func TestConditionsFromMap(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "JSON paths",
+			args: args{
+				m: map[string][]string{
+					"attrs":            {"foo"},
+					"attrs.size":       {"gte:10"},
+					"attrs.color.name": {"red"},
+				},
+				f: fields.From(
+					fields.Field{Name: "attrs", Kind: types.JSON},
+				),
+			},
+			want: []Condition{
+				{Attribute: "attrs.color.name", Operator: Equals, Value: "red"},
+				{Attribute: "attrs.size", Operator: GreaterOrEqualTo, Value: float64(10)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "JSON invalid path",
+			args: args{
+				m: map[string][]string{
+					"attrs.col'or": {"red"},
+				},
+				f: fields.From(
+					fields.Field{Name: "attrs", Kind: types.JSON},
+				),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -565,3 +632,18 @@ func TestConditionsFromMap(t *testing.T) {
 		})
 	}
 }
+
+func TestNewError(t *testing.T) {
+	var (
+		err     = NewError(ErrInvalid, "invalid subscription")
+		wrapped = fmt.Errorf("%w: url must be absolute", err)
+	)
+
+	if err.Error() != "invalid subscription" || !errors.Is(wrapped, err) || !errors.Is(wrapped, ErrInvalid) {
+		t.Errorf("NewError() = %v, want an error of kind %v", wrapped, ErrInvalid)
+	}
+
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("NewError() of kind %v is also %v", ErrInvalid, ErrNotFound)
+	}
+}
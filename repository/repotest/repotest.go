@@ -0,0 +1,166 @@
+// Package repotest provides an in-memory repository to test packages built upon repositories
+package repotest
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func init() {
+	var _ repository.Repositorium = &Memory{}
+}
+
+// Memory is a repository of records per entity, kept in order of creation. Conditions are evaluated as by
+// repository.Match, except that a record lacking the attribute of a condition does not match it. Pagination is
+// ignored. Safe for concurrent use; the zero value is ready to use.
+type Memory struct {
+	mu   sync.Mutex
+	rows map[string][]*values.Values
+	next int
+}
+
+// Rows of an entity, in order of creation; the records themselves are returned so that tests can inspect them
+func (m *Memory) Rows(name string) []*values.Values {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*values.Values(nil), m.rows[name]...)
+}
+
+// matches checks whether a record holds every attribute of the conditions and satisfies them
+func matches(v *values.Values, c []repository.Condition) bool {
+	for i := range c {
+		var name, _ = repository.SplitPath(c[i].Attribute)
+		if v.Get(name) == nil || !repository.Match(v, c[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// where returns records of an entity satisfying conditions; to be called with the lock held
+func (m *Memory) where(name string, c []repository.Condition) []*values.Values {
+	var l []*values.Values
+	for _, v := range m.rows[name] {
+		if matches(v, c) {
+			l = append(l, v)
+		}
+	}
+
+	return l
+}
+
+// Get a single Name by id
+func (m *Memory) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if l := m.where(entity.Name(), []repository.Condition{{Attribute: "id", Value: id}}); len(l) != 0 {
+		return l[0].Clone(), nil
+	}
+
+	return nil, repository.ErrNotFound
+}
+
+// List multiple Name with conditions
+func (m *Memory) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var l []values.Values
+	for _, v := range m.where(entity.Name(), c) {
+		l = append(l, *v.Clone())
+	}
+
+	return l, nil
+}
+
+// Delete a single Name by id
+func (m *Memory) Delete(ctx context.Context, named repository.Named, id string) error {
+	return m.DeleteWhere(ctx, named, repository.Condition{Attribute: "id", Value: id})
+}
+
+// DeleteWhere delete multiple Name based on conditions
+func (m *Memory) DeleteWhere(ctx context.Context, named repository.Named, c ...repository.Condition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var kept []*values.Values
+	for _, v := range m.rows[named.Name()] {
+		if !matches(v, c) {
+			kept = append(kept, v)
+		}
+	}
+
+	m.rows[named.Name()] = kept
+
+	return nil
+}
+
+// Create a new Name, identified by a sequence number
+func (m *Memory) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rows == nil {
+		m.rows = make(map[string][]*values.Values)
+	}
+
+	m.next++
+
+	var (
+		id = strconv.Itoa(m.next)
+		v  = vals.Clone()
+	)
+
+	v.Set("id", id)
+	m.rows[named.Name()] = append(m.rows[named.Name()], v)
+
+	return id, nil
+}
+
+// Update an existing Name
+func (m *Memory) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var l = m.where(named.Name(), []repository.Condition{{Attribute: "id", Value: id}})
+	if len(l) == 0 {
+		return repository.ErrNotFound
+	}
+
+	set(l, vals)
+
+	return nil
+}
+
+// UpdateWhere Values of Name satisfying conditions
+func (m *Memory) UpdateWhere(ctx context.Context, named repository.Named, vals *values.Values, c ...repository.Condition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	set(m.where(named.Name(), c), vals)
+
+	return nil
+}
+
+// set values on records
+func set(l []*values.Values, vals *values.Values) {
+	for _, v := range l {
+		var it = vals.Iterator()
+		for it.Next() {
+			v.Set(it.Value().Name, it.Value().Value)
+		}
+	}
+}
+
+// Close does nothing
+func (m *Memory) Close() error {
+	return nil
+}
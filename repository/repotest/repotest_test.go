@@ -0,0 +1,42 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func TestMemory(t *testing.T) {
+	var (
+		ctx = context.Background()
+		m   Memory
+		e   = entity.ID("note")
+	)
+
+	var id, _ = m.Create(ctx, e, values.FromMap(map[string]interface{}{"text": "foo"}))
+	m.Create(ctx, e, values.FromMap(map[string]interface{}{"text": "bar", "owner": "ada"}))
+
+	if l, err := m.List(ctx, e, repository.Pagination{}, repository.Condition{Attribute: "owner", Value: "ada"}); err != nil || len(l) != 1 {
+		t.Errorf("List() on attribute absent from a record = %v, %v, want 1 record", l, err)
+	}
+
+	if err := m.Update(ctx, e, id, values.FromMap(map[string]interface{}{"text": "baz"})); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+
+	if v, err := m.Get(ctx, e, id); err != nil || v.Get("text").String() != "baz" {
+		t.Errorf("Get() = %v, %v", v, err)
+	}
+
+	m.Delete(ctx, e, id)
+	if _, err := m.Get(ctx, e, id); err != repository.ErrNotFound {
+		t.Errorf("Get() of deleted record error = %v, want %v", err, repository.ErrNotFound)
+	}
+
+	if err := m.Update(ctx, e, id, values.FromMap(map[string]interface{}{"text": "qux"})); err != repository.ErrNotFound {
+		t.Errorf("Update() of deleted record error = %v, want %v", err, repository.ErrNotFound)
+	}
+}
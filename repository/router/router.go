@@ -0,0 +1,399 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/telemetry"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+var (
+	// ErrUnknownTenant when no data source is registered for the tenant of the context; a kind of repository.ErrNotFound
+	ErrUnknownTenant = repository.NewError(repository.ErrNotFound, "unknown tenant")
+
+	// ErrClosed when the router is used after Close
+	ErrClosed = errors.New("router closed")
+)
+
+func init() {
+	var _ repository.Repositorium = &Router{}
+	var _ repository.Aggregator = &Router{}
+	var _ repository.Searcher = &Router{}
+	var _ repository.Streamer = &Router{}
+	var _ repository.Counter = &Router{}
+}
+
+// Registry resolves the data source of tenants, example a mysql dsn or a mongo database name
+type Registry interface {
+	// Source of a tenant; ErrUnknownTenant if there is none
+	Source(tenant string) (string, error)
+}
+
+// Static is a registry of fixed tenant => data source
+type Static map[string]string
+
+// Source of a tenant
+func (s Static) Source(tenant string) (string, error) {
+	if src, ok := s[tenant]; ok {
+		return src, nil
+	}
+
+	return "", ErrUnknownTenant
+}
+
+// Opener opens a repository on a data source
+type Opener func(source string) (repository.Repositorium, error)
+
+// conn is the repository of a tenant, usable once ready is closed unless err is set
+type conn struct {
+	repo  repository.Repositorium
+	err   error
+	ready chan struct{}
+	users int
+	used  time.Time
+}
+
+// Router is a repository routing each operation to the repository of the tenant of the context. Repositories are opened
+// on first use and closed once idle for longer than the idle duration.
+type Router struct {
+	mu       sync.Mutex
+	registry Registry
+	open     Opener
+	idle     time.Duration
+	conns    map[string]*conn
+	closed   bool
+	now      func() time.Time
+
+	// Telemetry logs failures to close idle repositories; optional
+	Telemetry *telemetry.Telemetry
+}
+
+// New router opening repositories of tenants on the data sources of a registry; a zero idle duration keeps them open
+// until Close
+func New(registry Registry, open Opener, idle time.Duration) *Router {
+	return &Router{
+		registry: registry,
+		open:     open,
+		idle:     idle,
+		conns:    make(map[string]*conn),
+		now:      time.Now,
+	}
+}
+
+// acquire the repository of the tenant of the context, opening it if needed; it must be released once used. Opening
+// happens outside the lock so that tenants already open are not held up; concurrent users of a tenant being opened wait
+// for it.
+func (r *Router) acquire(ctx context.Context) (repository.Repositorium, func(), error) {
+	var t = tenant.Get(ctx)
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, nil, ErrClosed
+	}
+
+	var idle = r.evict()
+
+	var c, ok = r.conns[t]
+	if !ok {
+		c = &conn{ready: make(chan struct{})}
+		r.conns[t] = c
+	}
+
+	c.users++
+	r.mu.Unlock()
+
+	r.closeIdle(idle)
+
+	var released bool
+	var release = func() {
+		r.mu.Lock()
+		if !released {
+			released = true
+			c.users--
+			c.used = r.now()
+		}
+		r.mu.Unlock()
+	}
+
+	if !ok {
+		r.connect(t, c)
+	}
+
+	select {
+	case <-c.ready:
+	case <-ctx.Done():
+		release()
+		return nil, nil, ctx.Err()
+	}
+
+	if c.err != nil {
+		release()
+		return nil, nil, c.err
+	}
+
+	return c.repo, release, nil
+}
+
+// connect opens the repository of a tenant; it is forgotten on failure so that the next use tries again
+func (r *Router) connect(t string, c *conn) {
+	var src, err = r.registry.Source(t)
+	if err == nil {
+		c.repo, err = r.open(src)
+	}
+
+	if err != nil {
+		c.err = err
+
+		r.mu.Lock()
+		if r.conns[t] == c {
+			delete(r.conns, t)
+		}
+		r.mu.Unlock()
+	}
+
+	close(c.ready)
+}
+
+// evict detaches repositories unused for longer than the idle duration, to be closed once the lock is released (see
+// closeIdle); the lock must be held
+func (r *Router) evict() map[string]repository.Repositorium {
+	if r.idle == 0 {
+		return nil
+	}
+
+	var (
+		now  = r.now()
+		idle map[string]repository.Repositorium
+	)
+
+	for t, c := range r.conns {
+		if c.users == 0 && now.Sub(c.used) > r.idle {
+			if idle == nil {
+				idle = make(map[string]repository.Repositorium)
+			}
+
+			idle[t] = c.repo
+			delete(r.conns, t)
+		}
+	}
+
+	return idle
+}
+
+// closeIdle repositories evicted, logging failures; returns the first error encountered
+func (r *Router) closeIdle(idle map[string]repository.Repositorium) error {
+	var err error
+
+	for t, repo := range idle {
+		if e := repo.Close(); e != nil {
+			r.Telemetry.Log().Error("closing idle repository failed", "tenant", t, "error", e)
+
+			if err == nil {
+				err = e
+			}
+		}
+	}
+
+	return err
+}
+
+// Evict repositories idle for longer than the idle duration; eviction also happens as tenants are routed. Returns the
+// first error closing them.
+func (r *Router) Evict() error {
+	r.mu.Lock()
+	var idle = r.evict()
+	r.mu.Unlock()
+
+	return r.closeIdle(idle)
+}
+
+// Len returns the number of open repositories
+func (r *Router) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.conns)
+}
+
+// Get a single Name by id
+func (r *Router) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	return repo.Get(ctx, entity, id)
+}
+
+// List multiple Name with pagination rules and conditions
+func (r *Router) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	return repo.List(ctx, entity, p, c...)
+}
+
+// cursor releases the repository it reads from when closed
+type cursor struct {
+	values.Cursor
+	release func()
+}
+
+// Close the cursor and release its repository
+func (c *cursor) Close() error {
+	defer c.release()
+	return c.Cursor.Close()
+}
+
+// Stream multiple Name with pagination rules and conditions; the repository is in use until the cursor is closed
+func (r *Router) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cur values.Cursor
+	cur, err = repository.Stream(ctx, repo, entity, p, c...)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	return &cursor{Cursor: cur, release: release}, nil
+}
+
+// Search items matching terms
+func (r *Router) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	return repository.Search(ctx, repo, entity, terms, p, c...)
+}
+
+// Count Name satisfying conditions
+func (r *Router) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer release()
+
+	return repository.CountOf(ctx, repo, entity, c...)
+}
+
+// Aggregate items of an entity
+func (r *Router) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	if agg, ok := repo.(repository.Aggregator); ok {
+		return agg.Aggregate(ctx, entity, a)
+	}
+
+	return nil, repository.ErrNotSupported
+}
+
+// Delete a single Name by id
+func (r *Router) Delete(ctx context.Context, named repository.Named, id string) error {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	return repo.Delete(ctx, named, id)
+}
+
+// DeleteWhere delete multiple Name based on conditions
+func (r *Router) DeleteWhere(ctx context.Context, named repository.Named, c ...repository.Condition) error {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	return repo.DeleteWhere(ctx, named, c...)
+}
+
+// Create a new Name in persistent storage
+func (r *Router) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer release()
+
+	return repo.Create(ctx, named, vals)
+}
+
+// Update an existing Name in persistent storage
+func (r *Router) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	return repo.Update(ctx, named, id, vals)
+}
+
+// UpdateWhere Values in persistent storage
+func (r *Router) UpdateWhere(ctx context.Context, named repository.Named, vals *values.Values, c ...repository.Condition) error {
+	var repo, release, err = r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	return repo.UpdateWhere(ctx, named, vals, c...)
+}
+
+// Close repositories of all tenants, waiting for those being opened; returns the first error encountered. The router
+// can no longer be used: operations fail with ErrClosed.
+func (r *Router) Close() error {
+	r.mu.Lock()
+	var conns = r.conns
+	r.conns = make(map[string]*conn)
+	r.closed = true
+	r.mu.Unlock()
+
+	var err error
+	for _, c := range conns {
+		<-c.ready
+
+		if c.err == nil {
+			if e := c.repo.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,159 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// source is a repository reporting its data source in records, and whether it is closed
+type source struct {
+	repository.Repositorium
+	name   string
+	closed bool
+	fail   error
+}
+
+func (s *source) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	return values.FromMap(map[string]interface{}{"source": s.name}), nil
+}
+
+func (s *source) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	return []values.Values{*values.FromMap(map[string]interface{}{"source": s.name})}, nil
+}
+
+func (s *source) Close() error {
+	s.closed = true
+	return s.fail
+}
+
+func TestRouter(t *testing.T) {
+	var (
+		opened []*source
+		now    = time.Now()
+		e      = entity.ID("note")
+		foo    = tenant.Set(context.Background(), "foo")
+		bar    = tenant.Set(context.Background(), "bar")
+	)
+
+	var r = New(Static{"foo": "db_foo", "bar": "db_bar"}, func(src string) (repository.Repositorium, error) {
+		var s = &source{name: src}
+		opened = append(opened, s)
+		return s, nil
+	}, time.Minute)
+
+	r.now = func() time.Time { return now }
+
+	var from = func(ctx context.Context, want string) {
+		t.Helper()
+		if v, err := r.Get(ctx, e, "1"); err != nil || v.Get("source").String() != want {
+			t.Errorf("Get() = %v, %v, want source %s", v, err, want)
+		}
+	}
+
+	from(foo, "db_foo")
+	from(bar, "db_bar")
+	from(foo, "db_foo")
+
+	if len(opened) != 2 {
+		t.Errorf("opened %d repositories, want 2", len(opened))
+	}
+
+	if _, err := r.Get(tenant.Set(context.Background(), "baz"), e, "1"); err != ErrUnknownTenant {
+		t.Errorf("Get() of unknown tenant error = %v, want %v", err, ErrUnknownTenant)
+	}
+
+	if _, err := r.Get(context.Background(), e, "1"); err != ErrUnknownTenant {
+		t.Errorf("Get() without tenant error = %v, want %v", err, ErrUnknownTenant)
+	}
+
+	var cur, _ = r.Stream(bar, e, repository.Pagination{})
+
+	now = now.Add(2 * time.Minute)
+	r.Evict()
+
+	if !opened[0].closed || opened[1].closed || r.Len() != 1 {
+		t.Errorf("Evict() closed foo %v, bar %v, want foo only", opened[0].closed, opened[1].closed)
+	}
+
+	cur.Close()
+	cur.Close()
+	now = now.Add(2 * time.Minute)
+	from(foo, "db_foo")
+
+	if !opened[1].closed || len(opened) != 3 || r.Len() != 1 {
+		t.Errorf("bar not evicted once its cursor is closed")
+	}
+
+	r.Close()
+
+	if !opened[2].closed || r.Len() != 0 {
+		t.Errorf("Close() did not close all repositories")
+	}
+
+	if _, err := r.Get(foo, e, "1"); err != ErrClosed || len(opened) != 3 {
+		t.Errorf("Get() after Close() error = %v, opened %d, want %v and no repository opened", err, len(opened), ErrClosed)
+	}
+}
+
+func TestRouter_open(t *testing.T) {
+	var (
+		e       = entity.ID("note")
+		mu      sync.Mutex
+		opens   = map[string]int{}
+		proceed = make(chan struct{})
+		failure = errors.New("connection reset")
+	)
+
+	var r = New(Static{"slow": "db_slow", "fast": "db_fast"}, func(src string) (repository.Repositorium, error) {
+		mu.Lock()
+		opens[src]++
+		mu.Unlock()
+
+		if src == "db_slow" {
+			<-proceed
+		}
+
+		return &source{name: src, fail: failure}, nil
+	}, time.Minute)
+
+	var (
+		wg   sync.WaitGroup
+		slow = tenant.Set(context.Background(), "slow")
+	)
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := r.Get(slow, e, "1"); err != nil || v.Get("source").String() != "db_slow" {
+				t.Errorf("Get() of slow = %v, %v", v, err)
+			}
+		}()
+	}
+
+	if _, err := r.Get(tenant.Set(context.Background(), "fast"), e, "1"); err != nil {
+		t.Errorf("Get() of fast while slow opens error = %v", err)
+	}
+
+	close(proceed)
+	wg.Wait()
+
+	if opens["db_slow"] != 1 || opens["db_fast"] != 1 {
+		t.Errorf("opened %v, want each source once", opens)
+	}
+
+	var now = time.Now().Add(2 * time.Minute)
+	r.now = func() time.Time { return now }
+
+	if err := r.Evict(); err != failure || r.Len() != 0 {
+		t.Errorf("Evict() error = %v, want %v", err, failure)
+	}
+}
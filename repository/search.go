@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+var (
+	// ErrNotSearchable when searching an entity which declares no search fields
+	ErrNotSearchable = errors.New("entity is not searchable")
+)
+
+// Searcher is a repository able to search the search fields of an entity (see entity.Searchable) by full-text
+type Searcher interface {
+	// Search items matching terms, most relevant first; pagination ordering applies to items of equal relevance
+	Search(ctx context.Context, entity entity.Entity, terms string, p Pagination, c ...Condition) ([]values.Values, error)
+}
+
+// Search items of a searchable entity matching terms, most relevant first. If the repository is not a Searcher, items
+// satisfying the conditions are listed then matched and ranked in process.
+func Search(ctx context.Context, repo Repositorium, e entity.Entity, terms string, p Pagination, c ...Condition) ([]values.Values, error) {
+	var search = entity.SearchFieldsOf(e)
+	if len(search) == 0 {
+		return nil, ErrNotSearchable
+	}
+
+	if s, ok := repo.(Searcher); ok {
+		return s.Search(ctx, e, terms, p, c...)
+	}
+
+	// search fields are needed for ranking even if e is projected onto other fields
+	var (
+		f     fields.Fields
+		extra []string
+		it    = e.Fields().Iterator()
+	)
+
+	for it.Next() {
+		f.Add(*it.Field())
+	}
+
+	for _, name := range search {
+		if !f.Contains(name) {
+			f.Add(fields.Field{Name: name, Kind: types.String})
+			extra = append(extra, name)
+		}
+	}
+
+	var list, err = repo.List(ctx, entity.Partial(e.Name(), f), Pagination{Order: p.Order}, c...)
+	if err != nil {
+		return nil, err
+	}
+
+	list = Rank(list, search, terms)
+
+	for i := range list {
+		for _, name := range extra {
+			list[i].Unset(name)
+		}
+	}
+
+	if p.Offset >= len(list) {
+		return nil, nil
+	}
+
+	list = list[p.Offset:]
+	if p.Limit != 0 && p.Limit < len(list) {
+		list = list[:p.Limit]
+	}
+
+	return list, nil
+}
+
+// Rank returns the records matching terms, most relevant first; the relevance of a record is the number of occurrences
+// of terms within its fields. Records of equal relevance keep their order.
+func Rank(records []values.Values, search []string, terms string) []values.Values {
+	var words = Terms(terms)
+	if len(words) == 0 {
+		return nil
+	}
+
+	type ranked struct {
+		score int
+		vals  values.Values
+	}
+
+	var matches []ranked
+
+	for i := range records {
+		var score int
+
+		for _, f := range search {
+			var s = strings.ToLower(text(records[i].Get(f)))
+			for _, w := range words {
+				score += strings.Count(s, w)
+			}
+		}
+
+		if score != 0 {
+			matches = append(matches, ranked{score: score, vals: records[i]})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	var list = make([]values.Values, len(matches))
+	for i := range matches {
+		list[i] = matches[i].vals
+	}
+
+	return list
+}
+
+// Terms splits a search query into lowercase words
+func Terms(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// text of a string value, which may be a pointer
+func text(v *values.Value) string {
+	if v == nil {
+		return ""
+	}
+
+	switch s := v.Value.(type) {
+	case string:
+		return s
+	case *string:
+		if s != nil {
+			return *s
+		}
+	}
+
+	return ""
+}
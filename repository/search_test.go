@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fluxynet/gocipe/values"
+)
+
+func TestRank(t *testing.T) {
+	var (
+		title = "Go in practice"
+		recs  = []values.Values{
+			*values.FromMap(map[string]interface{}{"id": "1", "title": "Cooking", "body": "pasta and rice"}),
+			*values.FromMap(map[string]interface{}{"id": "2", "title": "Go", "body": "go go go"}),
+			*values.FromMap(map[string]interface{}{"id": "3", "title": &title, "body": nil}),
+			*values.FromMap(map[string]interface{}{"id": "4", "title": "Rust", "body": "go away"}),
+		}
+	)
+
+	tests := []struct {
+		name  string
+		terms string
+		want  []string
+	}{
+		{name: "No terms", terms: " !", want: nil},
+		{name: "No match", terms: "java", want: nil},
+		{name: "Ranked", terms: "Go", want: []string{"2", "3", "4"}},
+		{name: "Several terms", terms: "rice, rust", want: []string{"1", "4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range Rank(recs, []string{"title", "body"}, tt.terms) {
+				got = append(got, v.Get("id").String())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
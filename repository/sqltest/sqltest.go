@@ -0,0 +1,201 @@
+// Package sqltest provides a database/sql driver standing in for a database in tests of sql repositories
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// Rows answering a query; Columns default to as many unnamed columns as the first row has values
+type Rows struct {
+	Columns []string
+	Values  [][]driver.Value
+}
+
+// DB is a stand-in database counting statements and commits. Queries are answered by Query and statements by Exec,
+// without rows nor error when these are nil. They are called one at a time and must not call methods of the DB.
+// A DB marked down fails pings. The zero value is ready to use.
+type DB struct {
+	Query func(query string, args []driver.Value) (*Rows, error)
+	Exec  func(query string, args []driver.Value) error
+
+	mu         sync.Mutex
+	statements int
+	commits    int
+	down       bool
+}
+
+// Open a connection pool to the database, closed at the end of the test
+func (d *DB) Open(t *testing.T) *sql.DB {
+	var db = sql.OpenDB(connector{db: d})
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return db
+}
+
+// Statements executed or queried so far
+func (d *DB) Statements() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.statements
+}
+
+// Commits of transactions so far
+func (d *DB) Commits() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.commits
+}
+
+// SetDown marks the database as down or back up
+func (d *DB) SetDown(down bool) {
+	d.mu.Lock()
+	d.down = down
+	d.mu.Unlock()
+}
+
+type connector struct {
+	db *DB
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return conn(c), nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return c
+}
+
+func (c connector) Open(name string) (driver.Conn, error) {
+	return conn(c), nil
+}
+
+type conn struct {
+	db *DB
+}
+
+func (c conn) Prepare(query string) (driver.Stmt, error) {
+	return stmt{db: c.db, query: query}, nil
+}
+
+func (conn) Close() error {
+	return nil
+}
+
+func (c conn) Begin() (driver.Tx, error) {
+	return tx(c), nil
+}
+
+func (c conn) Ping(ctx context.Context) error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	if c.db.down {
+		return errors.New("database is down")
+	}
+
+	return nil
+}
+
+type tx struct {
+	db *DB
+}
+
+func (t tx) Commit() error {
+	t.db.mu.Lock()
+	t.db.commits++
+	t.db.mu.Unlock()
+
+	return nil
+}
+
+func (tx) Rollback() error {
+	return nil
+}
+
+type stmt struct {
+	db    *DB
+	query string
+}
+
+func (stmt) Close() error {
+	return nil
+}
+
+func (stmt) NumInput() int {
+	return -1
+}
+
+func (s stmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.db.statements++
+
+	if s.db.Exec != nil {
+		if err := s.db.Exec(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.db.statements++
+
+	var r *Rows
+	if s.db.Query != nil {
+		var err error
+		if r, err = s.db.Query(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+
+	if r == nil {
+		r = &Rows{}
+	}
+
+	var columns = r.Columns
+	if columns == nil && len(r.Values) != 0 {
+		columns = make([]string, len(r.Values[0]))
+	}
+
+	return &rows{columns: columns, values: r.Values}, nil
+}
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (*rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+
+	return nil
+}
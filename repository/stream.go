@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// Streamer is a repository able to fetch records one at a time instead of all at once
+type Streamer interface {
+	// Stream multiple records with pagination rules and conditions; the cursor must be closed
+	Stream(ctx context.Context, entity entity.Entity, p Pagination, c ...Condition) (values.Cursor, error)
+}
+
+// Stream records of an entity; if the repository is not a Streamer, records are listed then iterated
+func Stream(ctx context.Context, repo Repositorium, e entity.Entity, p Pagination, c ...Condition) (values.Cursor, error) {
+	if s, ok := repo.(Streamer); ok {
+		return s.Stream(ctx, e, p, c...)
+	}
+
+	var list, err = repo.List(ctx, e, p, c...)
+	if err != nil {
+		return nil, err
+	}
+
+	return values.NewSliceCursor(list), nil
+}
@@ -0,0 +1,261 @@
+package tenancy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/changes"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// Attribute holding the tenant of records
+const Attribute = "tenant_id"
+
+var (
+	// ErrNoTenant when the context has no tenant
+	ErrNoTenant = errors.New("no tenant in context")
+)
+
+func init() {
+	var _ repository.Repositorium = &Repo{}
+	var _ repository.Aggregator = &Repo{}
+	var _ repository.Searcher = &Repo{}
+	var _ repository.Streamer = &Repo{}
+	var _ repository.Counter = &Repo{}
+	var _ changes.Watcher = &Repo{}
+}
+
+// Repo is a repository isolating the records of each tenant: every operation is restricted to records whose Attribute
+// is the tenant of the context, and records created are stamped with it. Operations fail with ErrNoTenant when the
+// context has no tenant. Conditions on Attribute given by callers are replaced and values of Attribute are ignored on
+// update, so that records can neither be read from nor moved to another tenant.
+type Repo struct {
+	repo repository.Repositorium
+}
+
+// New repository isolating tenants of another repository
+func New(repo repository.Repositorium) *Repo {
+	return &Repo{repo: repo}
+}
+
+// scope returns the conditions restricted to the tenant of the context
+func scope(ctx context.Context, c []repository.Condition) ([]repository.Condition, error) {
+	var t = tenant.Get(ctx)
+	if t == "" {
+		return nil, ErrNoTenant
+	}
+
+	var scoped = make([]repository.Condition, 0, len(c)+1)
+	for i := range c {
+		if c[i].Attribute != Attribute {
+			scoped = append(scoped, c[i])
+		}
+	}
+
+	return append(scoped, repository.Condition{Attribute: Attribute, Operator: repository.Equals, Value: t}), nil
+}
+
+// byID returns the conditions matching a record by id within the tenant of the context
+func byID(ctx context.Context, id string) ([]repository.Condition, error) {
+	return scope(ctx, []repository.Condition{{Attribute: "id", Operator: repository.Equals, Value: id}})
+}
+
+// owned checks that a record exists within the tenant of the context
+func (r *Repo) owned(ctx context.Context, named repository.Named, id string) error {
+	var c, err = byID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var l []values.Values
+	l, err = r.repo.List(ctx, entity.ID(named.Name()), repository.Pagination{Limit: 1}, c...)
+	if err == nil && len(l) == 0 {
+		err = repository.ErrNotFound
+	}
+
+	return err
+}
+
+// Get a single Name by id
+func (r *Repo) Get(ctx context.Context, entity entity.Entity, id string) (*values.Values, error) {
+	var c, err = byID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var l []values.Values
+	l, err = r.repo.List(ctx, entity, repository.Pagination{Limit: 1}, c...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(l) == 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	return &l[0], nil
+}
+
+// List multiple Name with pagination rules and conditions
+func (r *Repo) List(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var scoped, err = scope(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.repo.List(ctx, entity, p, scoped...)
+}
+
+// Stream multiple Name with pagination rules and conditions
+func (r *Repo) Stream(ctx context.Context, entity entity.Entity, p repository.Pagination, c ...repository.Condition) (values.Cursor, error) {
+	var scoped, err = scope(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Stream(ctx, r.repo, entity, p, scoped...)
+}
+
+// Search items matching terms
+func (r *Repo) Search(ctx context.Context, entity entity.Entity, terms string, p repository.Pagination, c ...repository.Condition) ([]values.Values, error) {
+	var scoped, err = scope(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Search(ctx, r.repo, entity, terms, p, scoped...)
+}
+
+// Count Name satisfying conditions
+func (r *Repo) Count(ctx context.Context, entity entity.Entity, c ...repository.Condition) (int64, error) {
+	var scoped, err = scope(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+
+	return repository.CountOf(ctx, r.repo, entity, scoped...)
+}
+
+// Aggregate items of an entity
+func (r *Repo) Aggregate(ctx context.Context, entity entity.Entity, a repository.Aggregation) ([]values.Values, error) {
+	var agg, ok = r.repo.(repository.Aggregator)
+	if !ok {
+		return nil, repository.ErrNotSupported
+	}
+
+	var err error
+	a.Conditions, err = scope(ctx, a.Conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	return agg.Aggregate(ctx, entity, a)
+}
+
+// Watch changes to items of an entity within the tenant of the context, if the underlying repository is a
+// changes.Watcher; changes of other tenants are dropped
+func (r *Repo) Watch(ctx context.Context, entity string, after string) (<-chan changes.Change, error) {
+	var w, ok = r.repo.(changes.Watcher)
+	if !ok {
+		return nil, repository.ErrNotSupported
+	}
+
+	var t = tenant.Get(ctx)
+	if t == "" {
+		return nil, ErrNoTenant
+	}
+
+	var in, err = w.Watch(ctx, entity, after)
+	if err != nil {
+		return nil, err
+	}
+
+	var out = make(chan changes.Change, changes.Buffer)
+
+	go func() {
+		defer close(out)
+
+		for c := range in {
+			if c.Tenant != t {
+				continue
+			}
+
+			select {
+			case out <- c:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Delete a single Name by id
+func (r *Repo) Delete(ctx context.Context, named repository.Named, id string) error {
+	var err = r.owned(ctx, named, id)
+	if err == nil {
+		err = r.repo.Delete(ctx, named, id)
+	}
+
+	return err
+}
+
+// DeleteWhere delete multiple Name based on conditions
+func (r *Repo) DeleteWhere(ctx context.Context, named repository.Named, c ...repository.Condition) error {
+	var scoped, err = scope(ctx, c)
+	if err == nil {
+		err = r.repo.DeleteWhere(ctx, named, scoped...)
+	}
+
+	return err
+}
+
+// Create a new Name in persistent storage, within the tenant of the context
+func (r *Repo) Create(ctx context.Context, named repository.Named, vals *values.Values) (string, error) {
+	var t = tenant.Get(ctx)
+	if t == "" {
+		return "", ErrNoTenant
+	}
+
+	vals.Set(Attribute, t)
+
+	return r.repo.Create(ctx, named, vals)
+}
+
+// Update an existing Name in persistent storage
+func (r *Repo) Update(ctx context.Context, named repository.Named, id string, vals *values.Values) error {
+	var err = r.owned(ctx, named, id)
+	if err == nil {
+		err = r.repo.Update(ctx, named, id, unscoped(vals))
+	}
+
+	return err
+}
+
+// UpdateWhere Values in persistent storage
+func (r *Repo) UpdateWhere(ctx context.Context, named repository.Named, vals *values.Values, c ...repository.Condition) error {
+	var scoped, err = scope(ctx, c)
+	if err == nil {
+		err = r.repo.UpdateWhere(ctx, named, unscoped(vals), scoped...)
+	}
+
+	return err
+}
+
+// unscoped removes the tenant from values to update
+func unscoped(vals *values.Values) *values.Values {
+	if vals != nil {
+		vals.Unset(Attribute)
+	}
+
+	return vals
+}
+
+// Close connection to the underlying repository
+func (r *Repo) Close() error {
+	return r.repo.Close()
+}
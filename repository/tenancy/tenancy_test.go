@@ -0,0 +1,145 @@
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/changes"
+	"github.com/fluxynet/gocipe/repository/repotest"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func TestRepo(t *testing.T) {
+	var (
+		mem  = &repotest.Memory{}
+		r    = New(mem)
+		e    = entity.Spec{EntityName: "note", FieldSpecs: []entity.FieldSpec{{Name: "text", Kind: types.String}}}
+		foo  = tenant.Set(context.Background(), "foo")
+		bar  = tenant.Set(context.Background(), "bar")
+		none = context.Background()
+	)
+
+	var text = func(s string) *values.Values {
+		return values.FromMap(map[string]interface{}{"text": s})
+	}
+
+	var count = func(ctx context.Context, want int) {
+		t.Helper()
+		if n, err := r.Count(ctx, e); err != nil || n != int64(want) {
+			t.Errorf("Count() = %d, %v, want %d", n, err, want)
+		}
+	}
+
+	var id, err = r.Create(foo, e, text("foo's"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Create(bar, e, values.FromMap(map[string]interface{}{"text": "bar's", Attribute: "foo"}))
+
+	if mem.Rows("note")[1].Get(Attribute).String() != "bar" {
+		t.Errorf("Create() did not stamp the tenant of the context")
+	}
+
+	if _, err = r.Create(none, e, text("nobody's")); err != ErrNoTenant {
+		t.Errorf("Create() without tenant error = %v, want %v", err, ErrNoTenant)
+	}
+
+	if _, err = r.Get(bar, e, id); err != repository.ErrNotFound {
+		t.Errorf("Get() of another tenant's record error = %v, want %v", err, repository.ErrNotFound)
+	}
+
+	if v, err := r.Get(foo, e, id); err != nil || v.Get("text").String() != "foo's" {
+		t.Errorf("Get() = %v, %v", v, err)
+	}
+
+	if l, _ := r.List(bar, e, repository.Pagination{}, repository.Condition{Attribute: Attribute, Value: "foo"}); len(l) != 1 || l[0].Get("text").String() != "bar's" {
+		t.Errorf("List() with condition on tenant = %v", l)
+	}
+
+	if _, err = r.List(none, e, repository.Pagination{}); err != ErrNoTenant {
+		t.Errorf("List() without tenant error = %v, want %v", err, ErrNoTenant)
+	}
+
+	if err = r.Update(bar, e, id, text("changed by bar")); err != repository.ErrNotFound {
+		t.Errorf("Update() of another tenant's record error = %v, want %v", err, repository.ErrNotFound)
+	}
+
+	r.UpdateWhere(bar, e, text("changed by bar"))
+	r.Update(foo, e, id, values.FromMap(map[string]interface{}{Attribute: "bar"}))
+
+	if v := mem.Rows("note")[0]; v.Get("text").String() != "foo's" || v.Get(Attribute).String() != "foo" {
+		t.Errorf("record of foo modified to %v", v)
+	}
+
+	if err = r.Delete(bar, e, id); err != repository.ErrNotFound {
+		t.Errorf("Delete() of another tenant's record error = %v, want %v", err, repository.ErrNotFound)
+	}
+
+	r.DeleteWhere(bar, e)
+	count(foo, 1)
+	count(bar, 0)
+
+	if err = r.DeleteWhere(none, e); err != ErrNoTenant {
+		t.Errorf("DeleteWhere() without tenant error = %v, want %v", err, ErrNoTenant)
+	}
+
+	if err = r.Delete(foo, e, id); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	count(foo, 0)
+}
+
+// source is a repository whose changes are those given, whatever the tenant watching
+type source struct {
+	repotest.Memory
+	changes []changes.Change
+}
+
+func (s *source) Watch(ctx context.Context, entity string, after string) (<-chan changes.Change, error) {
+	var ch = make(chan changes.Change, len(s.changes))
+	for _, c := range s.changes {
+		ch <- c
+	}
+
+	close(ch)
+
+	return ch, nil
+}
+
+func TestRepo_Watch(t *testing.T) {
+	var (
+		foo = tenant.Set(context.Background(), "foo")
+		src = &source{changes: []changes.Change{
+			{ID: "1", Type: changes.Created, Entity: "note", Tenant: "foo"},
+			{ID: "2", Type: changes.Created, Entity: "note", Tenant: "bar"},
+			{ID: "3", Type: changes.Deleted, Entity: "note", Tenant: "foo"},
+		}}
+	)
+
+	var ch, err = New(src).Watch(foo, "note", "")
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	var ids []string
+	for c := range ch {
+		ids = append(ids, c.ID)
+	}
+
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "3" {
+		t.Errorf("Watch() = changes %v, want 1 and 3 of tenant foo", ids)
+	}
+
+	if _, err = New(src).Watch(context.Background(), "note", ""); err != ErrNoTenant {
+		t.Errorf("Watch() without tenant error = %v, want %v", err, ErrNoTenant)
+	}
+
+	if _, err = New(&repotest.Memory{}).Watch(foo, "note", ""); err != repository.ErrNotSupported {
+		t.Errorf("Watch() of repository without changes error = %v, want %v", err, repository.ErrNotSupported)
+	}
+}
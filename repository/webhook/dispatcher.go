@@ -0,0 +1,293 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fluxynet/gocipe/repository/changes"
+	"github.com/fluxynet/gocipe/repository/hooks"
+	"github.com/fluxynet/gocipe/repository/outbox"
+	"github.com/fluxynet/gocipe/telemetry"
+	"github.com/fluxynet/gocipe/tenant"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// Payload posted to subscribers
+type Payload struct {
+	// ID of the delivery, identical across retries so that receivers can discard duplicates
+	ID     string                 `json:"id"`
+	Event  changes.Type           `json:"event"`
+	Entity string                 `json:"entity"`
+	Data   map[string]interface{} `json:"data"`
+	At     time.Time              `json:"at"`
+}
+
+const (
+	// SignatureHeader holds the signature of the body, see Sign
+	SignatureHeader = "X-Webhook-Signature"
+
+	// DeliveryHeader holds the id of the delivery
+	DeliveryHeader = "X-Webhook-Delivery"
+
+	// EventHeader holds the event type
+	EventHeader = "X-Webhook-Event"
+)
+
+// Dispatcher delivers changes to the subscriptions of a store, in the background. Deliveries responded with a status
+// other than 2xx are retried with backoff; each attempt is logged in the store.
+type Dispatcher struct {
+	Store *Store
+
+	// Client used for deliveries; defaults to a client with a 10s timeout which refuses to connect to addresses which
+	// are not public, such as loopback, private and link-local ones
+	Client *http.Client
+
+	// Attempts of delivery before giving up; defaults to 5
+	Attempts int
+
+	// Backoff returns the delay before the next attempt after attempts failed; defaults to outbox.ExponentialBackoff
+	Backoff func(attempts int) time.Duration
+
+	// Telemetry logs changes which could not be dispatched and deliveries which could not be logged; optional
+	Telemetry *telemetry.Telemetry
+
+	once   sync.Once
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// ErrPrivateAddress is when a delivery would connect to an address which is not public
+var ErrPrivateAddress = errors.New("address is not public")
+
+// defaultClient for deliveries; proxies are not used so that the addresses connected to are those of receivers
+var defaultClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: func(network, address string, c syscall.RawConn) error {
+				var host, _, err = net.SplitHostPort(address)
+				if ip := net.ParseIP(host); err != nil || ip == nil || !public(ip) {
+					return ErrPrivateAddress
+				}
+
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+}
+
+// private networks, including loopback, shared (carrier-grade nat) and link-local ones such as that of cloud metadata
+var private = networks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+func networks(cidrs ...string) []*net.IPNet {
+	var nets = make([]*net.IPNet, len(cidrs))
+	for i := range cidrs {
+		_, nets[i], _ = net.ParseCIDR(cidrs[i])
+	}
+
+	return nets
+}
+
+// public checks if an ip may be delivered to: it must be unicast and outside private networks
+func public(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, n := range private {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Register after hooks dispatching successful creates, updates and deletes of a repository
+func (d *Dispatcher) Register(h *hooks.Repo) *hooks.Repo {
+	var hook = func(t changes.Type) hooks.After {
+		return func(ctx context.Context, e *hooks.Event) {
+			if e.Err != nil {
+				return
+			}
+
+			var c = changes.Change{Type: t, Entity: e.Entity, Tenant: tenant.Get(ctx)}
+			if e.Values != nil {
+				c.Values = e.Values.Clone()
+			} else {
+				c.Values = &values.Values{}
+			}
+
+			c.Values.Set("id", e.ID)
+			if err := d.Dispatch(ctx, c); err != nil {
+				d.Telemetry.Log().Error("webhook dispatch failed", "entity", c.Entity, "event", c.Type, "id", e.ID, "error", err)
+			}
+		}
+	}
+
+	return h.
+		After(hooks.Create, "", hook(changes.Created)).
+		After(hooks.Update, "", hook(changes.Updated)).
+		After(hooks.Delete, "", hook(changes.Deleted))
+}
+
+// Dispatch a change to the subscriptions of its entity; deliveries proceed in the background within the tenant of the
+// context, which may end before they do
+func (d *Dispatcher) Dispatch(ctx context.Context, c changes.Change) error {
+	d.start()
+
+	var subs, err = d.Store.List(ctx, c.Entity)
+	if err != nil {
+		return err
+	}
+
+	var p = Payload{
+		ID:     uuid.NewString(),
+		Event:  c.Type,
+		Entity: c.Entity,
+		Data:   c.Values.ToMap(),
+		At:     time.Now().UTC(),
+	}
+
+	var body []byte
+	if body, err = json.Marshal(p); err != nil {
+		return err
+	}
+
+	var (
+		item = c.Values.Get("id")
+		bctx = tenant.Set(d.ctx, tenant.Get(ctx))
+	)
+
+	for _, sub := range subs {
+		if !sub.Subscribed(c.Type) {
+			continue
+		}
+
+		var log = Delivery{Subscription: sub.ID, Event: c.Type}
+		if item != nil {
+			log.Item, _ = item.Value.(string)
+		}
+
+		d.wg.Add(1)
+		go d.deliver(bctx, sub, p.ID, body, log)
+	}
+
+	return nil
+}
+
+// deliver a body to a subscription, retrying until it is accepted, attempts are exhausted or the dispatcher is closed
+func (d *Dispatcher) deliver(ctx context.Context, sub Subscription, id string, body []byte, log Delivery) {
+	defer d.wg.Done()
+
+	var (
+		attempts = d.Attempts
+		backoff  = d.Backoff
+	)
+
+	if attempts == 0 {
+		attempts = 5
+	}
+
+	if backoff == nil {
+		backoff = outbox.ExponentialBackoff
+	}
+
+	// logs are written even if the dispatcher is closed during an attempt
+	var lctx = tenant.Set(context.Background(), tenant.Get(ctx))
+
+	for log.Attempt = 1; ; log.Attempt++ {
+		log.Status, log.Error = d.post(ctx, sub, id, log.Event, body)
+		log.At = time.Now().UTC()
+
+		if err := d.Store.Log(lctx, log); err != nil {
+			d.Telemetry.Log().Error("webhook delivery log failed", "subscription", sub.ID, "attempt", log.Attempt, "error", err)
+		}
+
+		if log.Error == "" || log.Attempt == attempts {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff(log.Attempt)):
+		}
+	}
+}
+
+// post a body to a subscription; returns the status responded and the error of a failed delivery
+func (d *Dispatcher) post(ctx context.Context, sub Subscription, id string, event changes.Type, body []byte) (int, string) {
+	var req, err = http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, body))
+	req.Header.Set(DeliveryHeader, id)
+	req.Header.Set(EventHeader, string(event))
+
+	var client = d.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	var res *http.Response
+	if res, err = client.Do(req); err != nil {
+		return 0, err.Error()
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, http.StatusText(res.StatusCode)
+	}
+
+	return res.StatusCode, ""
+}
+
+// start prepares the context of deliveries, once
+func (d *Dispatcher) start() {
+	d.once.Do(func() {
+		d.ctx, d.cancel = context.WithCancel(context.Background())
+	})
+}
+
+// Close cancels deliveries in progress and pending retries, then waits for them to stop
+func (d *Dispatcher) Close() error {
+	d.start()
+
+	d.cancel()
+	d.wg.Wait()
+
+	return nil
+}
+
+// Wait for deliveries in progress, including their retries
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
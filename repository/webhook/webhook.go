@@ -0,0 +1,307 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/changes"
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+var (
+	// ErrInvalidSubscription is when a subscription lacks an entity or has an invalid url or event; a kind of
+	// repository.ErrInvalid
+	ErrInvalidSubscription = repository.NewError(repository.ErrInvalid, "invalid subscription")
+)
+
+// Subscription to changes of an entity, delivered to a URL
+type Subscription struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Entity string `json:"entity"`
+
+	// Events subscribed to; all if empty
+	Events []changes.Type `json:"events,omitempty"`
+
+	// Secret signing deliveries, see Sign; generated if empty on creation
+	Secret string `json:"secret,omitempty"`
+}
+
+// Delivery is the log of an attempt to deliver a change to a subscription
+type Delivery struct {
+	ID           string       `json:"id"`
+	Subscription string       `json:"subscription"`
+	Event        changes.Type `json:"event"`
+	Item         string       `json:"item"`
+
+	// Attempt number, from 1
+	Attempt int `json:"attempt"`
+
+	// Status responded by the receiver; 0 if there was no response
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	At time.Time `json:"at"`
+}
+
+// Subscriptions is the entity of subscription records
+var Subscriptions = entity.Spec{
+	EntityName: "webhook_subscription",
+	Desc:       "Subscriptions of webhooks to changes of entities",
+	FieldSpecs: []entity.FieldSpec{
+		{Name: "id", Kind: types.String},
+		{Name: "url", Kind: types.String},
+		{Name: "entity", Kind: types.String},
+		{Name: "events", Kind: types.JSON},
+		{Name: "secret", Kind: types.String},
+	},
+	IndexSpecs: []entity.IndexSpec{
+		{Fields: []string{"entity"}},
+	},
+}
+
+// Deliveries is the entity of delivery logs
+var Deliveries = entity.Spec{
+	EntityName: "webhook_delivery",
+	Desc:       "Logs of attempts to deliver changes to webhooks",
+	FieldSpecs: []entity.FieldSpec{
+		{Name: "id", Kind: types.String},
+		{Name: "subscription", Kind: types.String},
+		{Name: "event", Kind: types.String},
+		{Name: "item", Kind: types.String},
+		{Name: "attempt", Kind: types.Int64},
+		{Name: "status", Kind: types.Int64},
+		{Name: "error", Kind: types.String},
+		{Name: "at", Kind: types.String},
+	},
+	IndexSpecs: []entity.IndexSpec{
+		{Fields: []string{"subscription", "at"}},
+	},
+}
+
+// Store persists subscriptions and delivery logs as records of Subscriptions and Deliveries in a repository
+type Store struct {
+	repo repository.Repositorium
+}
+
+// New store persisting in a repository
+func New(repo repository.Repositorium) *Store {
+	return &Store{repo: repo}
+}
+
+// Get a subscription by id
+func (s *Store) Get(ctx context.Context, id string) (Subscription, error) {
+	var vals, err = s.repo.Get(ctx, Subscriptions, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	var sub Subscription
+	err = decode(vals, &sub)
+
+	return sub, err
+}
+
+// List subscriptions to an entity, or all subscriptions if entity is empty
+func (s *Store) List(ctx context.Context, entity string) ([]Subscription, error) {
+	var c []repository.Condition
+	if entity != "" {
+		c = append(c, repository.Condition{Attribute: "entity", Operator: repository.Equals, Value: entity})
+	}
+
+	var l, err = s.repo.List(ctx, Subscriptions, repository.Pagination{}, c...)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs = make([]Subscription, len(l))
+	for i := range l {
+		if err = decode(&l[i], &subs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return subs, nil
+}
+
+// Create a subscription, generating its secret if empty
+func (s *Store) Create(ctx context.Context, sub Subscription) (Subscription, error) {
+	var err = sub.Validate()
+	if err == nil && sub.Secret == "" {
+		sub.Secret, err = NewSecret()
+	}
+
+	if err == nil {
+		sub.ID, err = s.repo.Create(ctx, Subscriptions, sub.Values())
+	}
+
+	return sub, err
+}
+
+// Update a subscription by id; its secret is kept if empty
+func (s *Store) Update(ctx context.Context, sub Subscription) error {
+	var err = sub.Validate()
+	if err != nil {
+		return err
+	}
+
+	var vals = sub.Values()
+	if sub.Secret == "" {
+		vals.Unset("secret")
+	}
+
+	return s.repo.Update(ctx, Subscriptions, sub.ID, vals)
+}
+
+// Delete a subscription by id, along with its delivery logs
+func (s *Store) Delete(ctx context.Context, id string) error {
+	var err = s.repo.Delete(ctx, Subscriptions, id)
+	if err == nil {
+		err = s.repo.DeleteWhere(ctx, Deliveries, repository.Condition{
+			Attribute: "subscription",
+			Operator:  repository.Equals,
+			Value:     id,
+		})
+	}
+
+	return err
+}
+
+// Log a delivery
+func (s *Store) Log(ctx context.Context, d Delivery) error {
+	var _, err = s.repo.Create(ctx, Deliveries, values.FromMap(map[string]interface{}{
+		"subscription": d.Subscription,
+		"event":        string(d.Event),
+		"item":         d.Item,
+		"attempt":      int64(d.Attempt),
+		"status":       int64(d.Status),
+		"error":        d.Error,
+		"at":           d.At.UTC().Format(time.RFC3339Nano),
+	}))
+
+	return err
+}
+
+// Deliveries logged for a subscription, latest first
+func (s *Store) Deliveries(ctx context.Context, subscription string, p repository.Pagination) ([]Delivery, error) {
+	p.Order = []repository.OrderBy{{Attribute: "at", Sort: repository.Descending}}
+
+	var l, err = s.repo.List(ctx, Deliveries, p, repository.Condition{
+		Attribute: "subscription",
+		Operator:  repository.Equals,
+		Value:     subscription,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var list = make([]Delivery, len(l))
+	for i := range l {
+		if err = decode(&l[i], &list[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+// Validate a subscription: the url must be absolute http(s) to a public host, the entity set and events known. Host
+// names are resolved on delivery, when addresses which are not public are refused (see Dispatcher.Client).
+func (s Subscription) Validate() error {
+	var u, err = url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be absolute http or https", ErrInvalidSubscription)
+	}
+
+	if ip := net.ParseIP(u.Hostname()); strings.EqualFold(u.Hostname(), "localhost") || (ip != nil && !public(ip)) {
+		return fmt.Errorf("%w: url must not target a private address", ErrInvalidSubscription)
+	}
+
+	if s.Entity == "" {
+		return fmt.Errorf("%w: entity missing", ErrInvalidSubscription)
+	}
+
+	for _, e := range s.Events {
+		if e != changes.Created && e != changes.Updated && e != changes.Deleted {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidSubscription, e)
+		}
+	}
+
+	return nil
+}
+
+// Subscribed checks if a subscription is to an event
+func (s Subscription) Subscribed(t changes.Type) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+
+	for _, e := range s.Events {
+		if e == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Values of a subscription record, without id
+func (s Subscription) Values() *values.Values {
+	var events = make([]interface{}, len(s.Events))
+	for i := range s.Events {
+		events[i] = string(s.Events[i])
+	}
+
+	return values.FromMap(map[string]interface{}{
+		"url":    s.URL,
+		"entity": s.Entity,
+		"events": events,
+		"secret": s.Secret,
+	})
+}
+
+// decode a record through json as drivers return json fields, numbers and pointers differently
+func decode(vals *values.Values, v interface{}) error {
+	var b, err = json.Marshal(vals.ToMap())
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+
+	return err
+}
+
+// NewSecret generates a random secret
+func NewSecret() (string, error) {
+	var b = make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// Sign a body with a secret: the hex encoded HMAC-SHA256 of the body, prefixed by "sha256="
+func Sign(secret string, body []byte) string {
+	var mac = hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify the signature of a body, as receivers do
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
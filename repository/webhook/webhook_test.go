@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/changes"
+	"github.com/fluxynet/gocipe/repository/hooks"
+	"github.com/fluxynet/gocipe/repository/repotest"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func TestSubscription_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  Subscription
+		ok   bool
+	}{
+		{name: "Valid", sub: Subscription{URL: "https://example.com/hook", Entity: "order"}, ok: true},
+		{name: "Events", sub: Subscription{URL: "http://example.com", Entity: "order", Events: []changes.Type{changes.Created}}, ok: true},
+		{name: "Relative url", sub: Subscription{URL: "/hook", Entity: "order"}},
+		{name: "Other scheme", sub: Subscription{URL: "ftp://example.com", Entity: "order"}},
+		{name: "No entity", sub: Subscription{URL: "https://example.com"}},
+		{name: "Unknown event", sub: Subscription{URL: "https://example.com", Entity: "order", Events: []changes.Type{"moved"}}},
+		{name: "Localhost", sub: Subscription{URL: "http://localhost:8080/hook", Entity: "order"}},
+		{name: "Loopback", sub: Subscription{URL: "http://127.0.0.1/hook", Entity: "order"}},
+		{name: "Loopback v6", sub: Subscription{URL: "http://[::1]/hook", Entity: "order"}},
+		{name: "Private", sub: Subscription{URL: "http://10.1.2.3/hook", Entity: "order"}},
+		{name: "Metadata", sub: Subscription{URL: "http://169.254.169.254/latest/meta-data", Entity: "order"}},
+		{name: "Public address", sub: Subscription{URL: "http://93.184.216.34/hook", Entity: "order"}, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err = tt.sub.Validate()
+			if (err == nil) != tt.ok || (err != nil && !errors.Is(err, ErrInvalidSubscription)) {
+				t.Errorf("Validate() error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+// receiverURL of a test server, by a name which its client resolves to the server
+const receiverURL = "http://receiver.test/hook"
+
+// clientOf a test server, connecting to it whatever the host requested
+func clientOf(receiver *httptest.Server) *http.Client {
+	var addr = receiver.Listener.Addr().String()
+
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+}
+
+func TestDefaultClient(t *testing.T) {
+	var receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request to %s delivered", r.Host)
+	}))
+
+	defer receiver.Close()
+
+	if _, err := defaultClient.Get(receiver.URL); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("Get() error = %v, want %v", err, ErrPrivateAddress)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []Payload
+		calls    int
+	)
+
+	var receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var body, _ = ioutil.ReadAll(r.Body)
+		if !Verify("s3cret", body, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var p Payload
+		json.Unmarshal(body, &p)
+
+		if r.Header.Get(DeliveryHeader) != p.ID || r.Header.Get(EventHeader) != string(p.Event) {
+			t.Errorf("headers %v do not match payload %v", r.Header, p)
+		}
+
+		received = append(received, p)
+	}))
+
+	defer receiver.Close()
+
+	var (
+		ctx   = context.Background()
+		store = New(&repotest.Memory{})
+		d     = &Dispatcher{Store: store, Client: clientOf(receiver), Backoff: func(int) time.Duration { return time.Millisecond }}
+		repo  = d.Register(hooks.New(&repotest.Memory{}))
+		order = entity.ID("order")
+	)
+
+	var sub, err = store.Create(ctx, Subscription{
+		URL:    receiverURL,
+		Entity: "order",
+		Events: []changes.Type{changes.Created, changes.Deleted},
+		Secret: "s3cret",
+	})
+
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err = store.Create(ctx, Subscription{URL: receiverURL, Entity: "user"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var id, _ = repo.Create(ctx, order, values.FromMap(map[string]interface{}{"total": 10}))
+	d.Wait()
+
+	repo.Update(ctx, order, id, values.FromMap(map[string]interface{}{"total": 20}))
+	repo.Delete(ctx, order, id)
+	d.Wait()
+
+	if len(received) != 2 || received[0].Event != changes.Created || received[1].Event != changes.Deleted {
+		t.Fatalf("received %v, want created then deleted", received)
+	}
+
+	if received[0].Entity != "order" || received[0].Data["id"] != id || received[0].Data["total"] != float64(10) {
+		t.Errorf("received %v", received[0])
+	}
+
+	var logs []Delivery
+	if logs, err = store.Deliveries(ctx, sub.ID, repository.Pagination{}); err != nil {
+		t.Fatalf("Deliveries() error = %v", err)
+	}
+
+	var statuses = map[int]int{}
+	for _, l := range logs {
+		statuses[l.Status]++
+
+		if l.Status == http.StatusServiceUnavailable && (l.Attempt != 1 || l.Error == "" || l.Event != changes.Created) {
+			t.Errorf("failed delivery logged as %v", l)
+		}
+
+		if l.Item != id || l.At.IsZero() {
+			t.Errorf("delivery logged as %v", l)
+		}
+	}
+
+	if len(logs) != 3 || statuses[http.StatusServiceUnavailable] != 1 || statuses[http.StatusOK] != 2 {
+		t.Errorf("logged %v, want a failure then 2 deliveries", logs)
+	}
+
+	if err = store.Delete(ctx, sub.ID); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+
+	if logs, _ = store.Deliveries(ctx, sub.ID, repository.Pagination{}); len(logs) != 0 {
+		t.Errorf("deliveries of deleted subscription %v", logs)
+	}
+}
+
+func TestDispatcher_attempts(t *testing.T) {
+	var (
+		calls    int
+		receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+	)
+
+	defer receiver.Close()
+
+	var (
+		ctx    = context.Background()
+		store  = New(&repotest.Memory{})
+		d      = &Dispatcher{Store: store, Client: clientOf(receiver), Attempts: 3, Backoff: func(int) time.Duration { return time.Millisecond }}
+		sub, _ = store.Create(ctx, Subscription{URL: receiverURL, Entity: "order"})
+	)
+
+	if sub.Secret == "" {
+		t.Errorf("Create() did not generate a secret")
+	}
+
+	d.Dispatch(ctx, changes.Change{Type: changes.Updated, Entity: "order", Values: values.FromMap(map[string]interface{}{"id": "1"})})
+	d.Wait()
+
+	if calls != 3 {
+		t.Errorf("receiver called %d times, want 3 attempts", calls)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/fluxynet/gocipe/storage"
 	"github.com/fluxynet/gocipe/tenant"
@@ -37,30 +38,108 @@ type disk struct {
 	Path string
 }
 
+// space returns the directory of the tenant of the context
+func (d disk) space(ctx context.Context) (string, error) {
+	var tnt = tenant.Get(ctx)
+	if tnt != "" && !tenant.Valid(tnt) {
+		return "", tenant.ErrInvalidName
+	}
+
+	return filepath.Join(d.Path, tnt), nil
+}
+
+// locate a path within the directory of the tenant of the context; paths resolving outside of it, or to the directory
+// itself unless allowed, are refused
+func (d disk) locate(ctx context.Context, path string, root bool) (string, error) {
+	var dir, err = d.space(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var loc = filepath.Join(dir, path)
+	if loc == dir && root {
+		return loc, nil
+	}
+
+	if !strings.HasPrefix(loc, dir+string(filepath.Separator)) {
+		return "", storage.ErrInvalidPath
+	}
+
+	return loc, nil
+}
+
 func (d disk) Store(ctx context.Context, filename string, contntts []byte) error {
-	var (
-		tnt = tenant.Get(ctx)
-		dir = filepath.Join(d.Path, tnt, filepath.Dir(filename))
-		err = os.MkdirAll(dir, 0744)
-	)
+	var loc, err = d.locate(ctx, filename, false)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(loc), 0744)
+	}
 
 	if err == nil {
-		err = os.WriteFile(filename, contntts, 0644)
+		err = os.WriteFile(loc, contntts, 0644)
 	}
 
 	return err
 }
 
+// Provision the directory of the tenant of the context
+func (d disk) Provision(ctx context.Context) error {
+	var dir, err = d.space(ctx)
+	if err != nil {
+		return err
+	}
+
+	return os.MkdirAll(dir, 0744)
+}
+
+// Purge the directory of the tenant of the context
+func (d disk) Purge(ctx context.Context) error {
+	if tenant.Get(ctx) == "" {
+		return tenant.ErrInvalidName
+	}
+
+	var dir, err = d.space(ctx)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// Delete a file or directory of the tenant of the context; the directory of the tenant itself cannot be deleted, see
+// Purge
 func (d disk) Delete(ctx context.Context, path string) error {
-	var (
-		tnt    = tenant.Get(ctx)
-		loc    = filepath.Join(d.Path, tnt, path)
-		_, err = os.Stat(loc)
-	)
+	var loc, err = d.locate(ctx, path, false)
+	if err != nil {
+		return err
+	}
 
-	if os.IsNotExist(err) {
+	if _, err = os.Stat(loc); os.IsNotExist(err) {
 		return nil
 	}
 
 	return os.RemoveAll(loc)
 }
+
+// Size of a file or directory of the tenant of the context, including all files within
+func (d disk) Size(ctx context.Context, path string) (int64, error) {
+	var loc, err = d.locate(ctx, path, true)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+
+	err = filepath.Walk(loc, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+
+		return err
+	})
+
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	return size, err
+}
@@ -0,0 +1,74 @@
+package disk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluxynet/gocipe/storage"
+	"github.com/fluxynet/gocipe/tenant"
+)
+
+func TestDisk(t *testing.T) {
+	var (
+		root   = t.TempDir()
+		s, _   = Disk(root)
+		d      = s.(disk)
+		acme   = tenant.Set(context.Background(), "acme")
+		globex = tenant.Set(context.Background(), "globex")
+	)
+
+	if err := d.Store(globex, "a/file", []byte("globex")); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		err  error
+	}{
+		{name: "File", path: "b/file"},
+		{name: "Cleaned within space", path: "b/../c/file"},
+		{name: "Other tenant", path: "../../globex/a/file", err: storage.ErrInvalidPath},
+		{name: "Parent", path: "../file", err: storage.ErrInvalidPath},
+		{name: "Empty", path: "", err: storage.ErrInvalidPath},
+		{name: "Space itself", path: "b/..", err: storage.ErrInvalidPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := d.Store(acme, tt.path, []byte("acme")); err != tt.err {
+				t.Errorf("Store() error = %v, want %v", err, tt.err)
+			}
+
+			if err := d.Delete(acme, tt.path); err != tt.err {
+				t.Errorf("Delete() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	if b, err := os.ReadFile(filepath.Join(root, "globex", "a", "file")); err != nil || string(b) != "globex" {
+		t.Errorf("file of other tenant = %q, %v", b, err)
+	}
+
+	if _, err := d.Size(acme, "../globex"); err != storage.ErrInvalidPath {
+		t.Errorf("Size() of other tenant error = %v, want %v", err, storage.ErrInvalidPath)
+	}
+
+	if n, err := d.Size(globex, ""); n != 6 || err != nil {
+		t.Errorf("Size() of space = %d, %v, want 6", n, err)
+	}
+
+	if err := d.Purge(context.Background()); err != tenant.ErrInvalidName {
+		t.Errorf("Purge() without tenant error = %v, want %v", err, tenant.ErrInvalidName)
+	}
+
+	if err := d.Purge(globex); err != nil {
+		t.Errorf("Purge() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "globex")); !os.IsNotExist(err) {
+		t.Errorf("space of purged tenant remains: %v", err)
+	}
+}
@@ -1,6 +1,14 @@
 package storage
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrInvalidPath is when a path is empty where a file is expected, or resolves outside of the space of the tenant
+	ErrInvalidPath = errors.New("invalid path")
+)
 
 // Storage manages file access
 type Storage interface {
@@ -10,3 +18,21 @@ type Storage interface {
 	// Delete a file or path
 	Delete(ctx context.Context, path string) error
 }
+
+// Provisioner is a storage which prepares space for tenants before use
+type Provisioner interface {
+	// Provision space for the tenant of the context, example its directory
+	Provision(ctx context.Context) error
+}
+
+// Purger is a storage able to remove the whole space of a tenant
+type Purger interface {
+	// Purge the space of the tenant of the context, which must be set
+	Purge(ctx context.Context) error
+}
+
+// Sizer is a storage able to report the space used by files
+type Sizer interface {
+	// Size in bytes of a file or path of the tenant of the context; the whole space of the tenant if path is empty
+	Size(ctx context.Context, path string) (int64, error)
+}
@@ -0,0 +1,67 @@
+package telemetry
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger writes structured records: a message followed by alternating keys and values. Its methods are those of
+// *slog.Logger, which can be used as is.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// Discard is a logger writing nothing
+var Discard Logger = discard{}
+
+type discard struct{}
+
+func (discard) Debug(msg string, args ...interface{}) {}
+func (discard) Info(msg string, args ...interface{})  {}
+func (discard) Warn(msg string, args ...interface{})  {}
+func (discard) Error(msg string, args ...interface{}) {}
+
+// StdLogger adapts a logger of the standard library: records are printed as their level and message followed by
+// key=value pairs. Debug records are printed only if debug is set.
+func StdLogger(l *log.Logger, debug bool) Logger {
+	return stdLogger{l: l, debug: debug}
+}
+
+type stdLogger struct {
+	l     *log.Logger
+	debug bool
+}
+
+func (s stdLogger) Debug(msg string, args ...interface{}) {
+	if s.debug {
+		s.print("DEBUG", msg, args)
+	}
+}
+
+func (s stdLogger) Info(msg string, args ...interface{})  { s.print("INFO", msg, args) }
+func (s stdLogger) Warn(msg string, args ...interface{})  { s.print("WARN", msg, args) }
+func (s stdLogger) Error(msg string, args ...interface{}) { s.print("ERROR", msg, args) }
+
+// print a record; values holding spaces are quoted
+func (s stdLogger) print(level, msg string, args []interface{}) {
+	var b strings.Builder
+
+	b.WriteString(level)
+	b.WriteByte(' ')
+	b.WriteString(msg)
+
+	for i := 0; i+1 < len(args); i += 2 {
+		var v = fmt.Sprint(args[i+1])
+		if v == "" || strings.ContainsAny(v, " \t\n\"") {
+			v = fmt.Sprintf("%q", v)
+		}
+
+		fmt.Fprintf(&b, " %v=%s", args[i], v)
+	}
+
+	s.l.Print(b.String())
+}
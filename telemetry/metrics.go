@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"expvar"
+	"strings"
+	"sync"
+)
+
+// Metrics records measurements, named after Prometheus conventions. Labels are alternating names and values, always
+// given in the same order for a metric. Adapters create metrics on first use.
+type Metrics interface {
+	// Count adds a positive value to a counter
+	Count(name string, v float64, labels ...string)
+
+	// Gauge adds a value, negative to decrease it, to a gauge
+	Gauge(name string, v float64, labels ...string)
+
+	// Observe a value, example a latency in seconds, in a histogram
+	Observe(name string, v float64, labels ...string)
+}
+
+// NopMetrics records nothing
+var NopMetrics Metrics = nopMetrics{}
+
+type nopMetrics struct{}
+
+func (nopMetrics) Count(name string, v float64, labels ...string)   {}
+func (nopMetrics) Gauge(name string, v float64, labels ...string)   {}
+func (nopMetrics) Observe(name string, v float64, labels ...string) {}
+
+// Expvar adapts the expvar package of the standard library, served as json by expvar.Handler. Each metric is published
+// as a map of its series keyed by labels, example "resource=order,status=200"; histograms are published as the count
+// and the sum of observations, under name_count and name_sum.
+func Expvar() Metrics {
+	return expvarMetrics{}
+}
+
+type expvarMetrics struct{}
+
+// published guards the creation of maps, which expvar refuses to publish twice
+var published sync.Mutex
+
+// series of a metric, published on first use
+func (expvarMetrics) series(name string, labels []string) (*expvar.Map, string) {
+	published.Lock()
+	defer published.Unlock()
+
+	var m, ok = expvar.Get(name).(*expvar.Map)
+	if !ok {
+		m = expvar.NewMap(name)
+	}
+
+	var pairs = make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+"="+labels[i+1])
+	}
+
+	return m, strings.Join(pairs, ",")
+}
+
+func (e expvarMetrics) Count(name string, v float64, labels ...string) {
+	var m, key = e.series(name, labels)
+	m.AddFloat(key, v)
+}
+
+func (e expvarMetrics) Gauge(name string, v float64, labels ...string) {
+	var m, key = e.series(name, labels)
+	m.AddFloat(key, v)
+}
+
+func (e expvarMetrics) Observe(name string, v float64, labels ...string) {
+	var m, key = e.series(name+"_count", labels)
+	m.Add(key, 1)
+
+	m, key = e.series(name+"_sum", labels)
+	m.AddFloat(key, v)
+}
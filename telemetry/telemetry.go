@@ -0,0 +1,79 @@
+// Package telemetry defines the logging, metrics and tracing of components as small interfaces, to be adapted to
+// libraries such as log/slog, the Prometheus client or OpenTelemetry. Adapters of the standard library are provided.
+package telemetry
+
+import (
+	"context"
+	"net/http"
+)
+
+// Telemetry of components: structured logs, metrics and traces. A nil Telemetry, or one with nil fields, records
+// nothing for those.
+type Telemetry struct {
+	Logger  Logger
+	Metrics Metrics
+	Tracer  Tracer
+}
+
+// Log returns the logger, Discard if none
+func (t *Telemetry) Log() Logger {
+	if t == nil || t.Logger == nil {
+		return Discard
+	}
+
+	return t.Logger
+}
+
+// Meter returns the metrics, NopMetrics if none
+func (t *Telemetry) Meter() Metrics {
+	if t == nil || t.Metrics == nil {
+		return NopMetrics
+	}
+
+	return t.Metrics
+}
+
+// Start a span with the tracer, or a span recording nothing if there is none
+func (t *Telemetry) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	if t == nil || t.Tracer == nil {
+		return NopTracer.Start(ctx, name, attrs...)
+	}
+
+	return t.Tracer.Start(ctx, name, attrs...)
+}
+
+// ResponseWriter records the status and size of a response; it flushes if the wrapped writer does, as event streams
+// need
+type ResponseWriter struct {
+	http.ResponseWriter
+	Status int
+	Bytes  int64
+}
+
+// WriteHeader records the status
+func (w *ResponseWriter) WriteHeader(status int) {
+	if w.Status == 0 {
+		w.Status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the bytes written; the status is 200 if none was written
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if w.Status == 0 {
+		w.Status = http.StatusOK
+	}
+
+	var n, err = w.ResponseWriter.Write(b)
+	w.Bytes += int64(n)
+
+	return n, err
+}
+
+// Flush the wrapped writer if it can
+func (w *ResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
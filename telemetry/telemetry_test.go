@@ -0,0 +1,67 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"expvar"
+	"log"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	var b bytes.Buffer
+
+	var l = StdLogger(log.New(&b, "", 0), false)
+	l.Debug("hidden")
+	l.Info("request served", "status", 200, "path", "/a b", "error", errors.New("x"))
+	l.Error("")
+
+	var want = "INFO request served status=200 path=\"/a b\" error=x\nERROR \n"
+	if b.String() != want {
+		t.Errorf("logged:\n%q\nwant:\n%q", b.String(), want)
+	}
+}
+
+func TestExpvar(t *testing.T) {
+	var m = Expvar()
+
+	m.Count("test_requests_total", 1, "resource", "order", "status", "200")
+	m.Count("test_requests_total", 2, "resource", "order", "status", "200")
+	m.Gauge("test_stored_bytes", -5)
+	m.Observe("test_latency_seconds", 0.5, "op", "get")
+	m.Observe("test_latency_seconds", 1, "op", "get")
+
+	var tests = []struct {
+		name string
+		key  string
+		want string
+	}{
+		{"test_requests_total", "resource=order,status=200", "3"},
+		{"test_stored_bytes", "", "-5"},
+		{"test_latency_seconds_count", "op=get", "2"},
+		{"test_latency_seconds_sum", "op=get", "1.5"},
+	}
+
+	for _, tt := range tests {
+		var m, _ = expvar.Get(tt.name).(*expvar.Map)
+		if m == nil || m.Get(tt.key) == nil || m.Get(tt.key).String() != tt.want {
+			t.Errorf("%s{%s} = %v, want %s", tt.name, tt.key, m, tt.want)
+		}
+	}
+}
+
+func TestTelemetry_nil(t *testing.T) {
+	var (
+		t9y  *Telemetry
+		ctx  = context.Background()
+		c, s = t9y.Start(ctx, "op", Attr("k", 1))
+	)
+
+	s.RecordError(errors.New("failed"))
+	s.End()
+
+	if c != ctx || t9y.Log() != Discard || t9y.Meter() != NopMetrics {
+		t.Errorf("nil telemetry records, want nothing recorded")
+	}
+}
@@ -0,0 +1,188 @@
+// Package telemetrytest provides telemetry recorded in memory to test instrumented components
+package telemetrytest
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/fluxynet/gocipe/telemetry"
+)
+
+func init() {
+	var _ telemetry.Logger = &Recorder{}
+	var _ telemetry.Metrics = &Recorder{}
+	var _ telemetry.Tracer = &Recorder{}
+}
+
+// Record logged
+type Record struct {
+	Level string
+	Msg   string
+	Args  []interface{}
+}
+
+// Span started
+type Span struct {
+	Name  string
+	Attrs []telemetry.Attribute
+	Err   error
+	Ended bool
+}
+
+// Recorder keeps logs, metrics and spans in memory. Counters and gauges are kept as their total, histograms as their
+// observations. Safe for concurrent use; the zero value is ready to use.
+type Recorder struct {
+	mu           sync.Mutex
+	logs         []Record
+	values       map[string]float64
+	observations map[string][]float64
+	spans        []*Span
+}
+
+// Telemetry recorded by the recorder
+func (r *Recorder) Telemetry() *telemetry.Telemetry {
+	return &telemetry.Telemetry{Logger: r, Metrics: r, Tracer: r}
+}
+
+// Logs recorded so far
+func (r *Recorder) Logs() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Record(nil), r.logs...)
+}
+
+// Value of a counter or gauge
+func (r *Recorder) Value(name string, labels ...string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.values[key(name, labels)]
+}
+
+// Observations of a histogram
+func (r *Recorder) Observations(name string, labels ...string) []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]float64(nil), r.observations[key(name, labels)]...)
+}
+
+// Spans started so far, in order
+func (r *Recorder) Spans() []Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var l = make([]Span, len(r.spans))
+	for i, s := range r.spans {
+		l[i] = *s
+	}
+
+	return l
+}
+
+// key of a series
+func key(name string, labels []string) string {
+	return name + "{" + strings.Join(labels, ",") + "}"
+}
+
+func (r *Recorder) log(level, msg string, args []interface{}) {
+	r.mu.Lock()
+	r.logs = append(r.logs, Record{Level: level, Msg: msg, Args: args})
+	r.mu.Unlock()
+}
+
+// Debug record
+func (r *Recorder) Debug(msg string, args ...interface{}) {
+	r.log("DEBUG", msg, args)
+}
+
+// Info record
+func (r *Recorder) Info(msg string, args ...interface{}) {
+	r.log("INFO", msg, args)
+}
+
+// Warn record
+func (r *Recorder) Warn(msg string, args ...interface{}) {
+	r.log("WARN", msg, args)
+}
+
+// Error record
+func (r *Recorder) Error(msg string, args ...interface{}) {
+	r.log("ERROR", msg, args)
+}
+
+func (r *Recorder) add(name string, v float64, labels []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.values == nil {
+		r.values = make(map[string]float64)
+	}
+
+	r.values[key(name, labels)] += v
+}
+
+// Count adds to a counter
+func (r *Recorder) Count(name string, v float64, labels ...string) {
+	r.add(name, v, labels)
+}
+
+// Gauge adds to a gauge
+func (r *Recorder) Gauge(name string, v float64, labels ...string) {
+	r.add(name, v, labels)
+}
+
+// Observe a value in a histogram
+func (r *Recorder) Observe(name string, v float64, labels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.observations == nil {
+		r.observations = make(map[string][]float64)
+	}
+
+	var k = key(name, labels)
+	r.observations[k] = append(r.observations[k], v)
+}
+
+// Start a span
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...telemetry.Attribute) (context.Context, telemetry.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var s = &Span{Name: name, Attrs: attrs}
+	r.spans = append(r.spans, s)
+
+	return ctx, span{r: r, s: s}
+}
+
+type span struct {
+	r *Recorder
+	s *Span
+}
+
+func (s span) SetAttributes(attrs ...telemetry.Attribute) {
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+
+	if !s.s.Ended {
+		s.s.Attrs = append(s.s.Attrs, attrs...)
+	}
+}
+
+func (s span) RecordError(err error) {
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+
+	if err != nil && !s.s.Ended {
+		s.s.Err = err
+	}
+}
+
+func (s span) End() {
+	s.r.mu.Lock()
+	s.s.Ended = true
+	s.r.mu.Unlock()
+}
@@ -0,0 +1,43 @@
+package telemetrytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fluxynet/gocipe/telemetry"
+)
+
+func TestRecorder(t *testing.T) {
+	var (
+		r   Recorder
+		t9y = r.Telemetry()
+		err = errors.New("failed")
+	)
+
+	t9y.Log().Warn("slow", "op", "get")
+	t9y.Meter().Count("requests_total", 1, "status", "200")
+	t9y.Meter().Count("requests_total", 1, "status", "200")
+	t9y.Meter().Observe("latency_seconds", 0.5)
+
+	var _, s = t9y.Start(context.Background(), "op", telemetry.Attr("k", 1))
+	s.RecordError(err)
+	s.End()
+	s.SetAttributes(telemetry.Attr("late", true))
+
+	if l := r.Logs(); len(l) != 1 || l[0].Level != "WARN" || l[0].Msg != "slow" {
+		t.Errorf("Logs() = %v", l)
+	}
+
+	if v := r.Value("requests_total", "status", "200"); v != 2 {
+		t.Errorf("Value() = %v, want 2", v)
+	}
+
+	if o := r.Observations("latency_seconds"); len(o) != 1 || o[0] != 0.5 {
+		t.Errorf("Observations() = %v, want [0.5]", o)
+	}
+
+	if l := r.Spans(); len(l) != 1 || l[0].Name != "op" || l[0].Err != err || !l[0].Ended || len(l[0].Attrs) != 1 {
+		t.Errorf("Spans() = %v", l)
+	}
+}
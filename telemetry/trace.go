@@ -0,0 +1,48 @@
+package telemetry
+
+import "context"
+
+// Attribute of a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr of a span
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is an operation within a trace
+type Span interface {
+	// SetAttributes adds or replaces attributes
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError marks the span as failed; nil errors are ignored
+	RecordError(err error)
+
+	// End the span; further calls have no effect
+	End()
+}
+
+// Tracer starts spans, to be adapted to a tracing library which also propagates traces across services; the methods
+// of spans started are safe for concurrent use
+type Tracer interface {
+	// Start a span, child of the span of the context if any; the context returned holds the span
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// NopTracer starts spans which record nothing
+var NopTracer Tracer = nopTracer{}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(attrs ...Attribute) {}
+func (nopSpan) RecordError(err error)            {}
+func (nopSpan) End()                             {}
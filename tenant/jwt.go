@@ -0,0 +1,72 @@
+package tenant
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken when a JWT is malformed, has an unexpected algorithm or an invalid signature
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrExpiredToken when the exp claim of a JWT is past
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// Verifier checks a JWT and returns its claims
+type Verifier func(token string) (map[string]interface{}, error)
+
+// HS256 returns a verifier of JWT signed with HMAC SHA-256 using secret; tokens past their exp claim are rejected
+func HS256(secret []byte) Verifier {
+	return func(token string) (map[string]interface{}, error) {
+		var parts = strings.Split(token, ".")
+		if len(parts) != 3 {
+			return nil, ErrInvalidToken
+		}
+
+		var header struct {
+			Alg string `json:"alg"`
+		}
+
+		if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+			return nil, ErrInvalidToken
+		}
+
+		var sig, err = base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+
+		var mac = hmac.New(sha256.New, secret)
+		mac.Write([]byte(parts[0] + "." + parts[1]))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return nil, ErrInvalidToken
+		}
+
+		var claims map[string]interface{}
+		if err = decodeSegment(parts[1], &claims); err != nil {
+			return nil, ErrInvalidToken
+		}
+
+		if exp, ok := claims["exp"].(float64); ok && time.Now().Unix() >= int64(exp) {
+			return nil, ErrExpiredToken
+		}
+
+		return claims, nil
+	}
+}
+
+// decodeSegment decodes a base64url encoded json segment of a JWT
+func decodeSegment(seg string, v interface{}) error {
+	var b, err = base64.RawURLEncoding.DecodeString(seg)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+
+	return err
+}
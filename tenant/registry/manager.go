@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/storage"
+	"github.com/fluxynet/gocipe/tenant"
+)
+
+const (
+	// StorageQuota is the key of Tenant.Quotas limiting bytes of assets stored
+	StorageQuota = "storage_bytes"
+
+	// RecordsQuota prefixes keys of Tenant.Quotas limiting records of an entity, example records.order
+	RecordsQuota = "records."
+)
+
+// Hook prepares or purges resources of a tenant, example creating its database schema; the context holds the tenant
+type Hook func(ctx context.Context, t Tenant) error
+
+// Manager handles the lifecycle of tenants
+type Manager struct {
+	Registry Registry
+
+	// Storage of files, provisioned (see storage.Provisioner) and purged (see storage.Purger) with tenants; optional
+	Storage storage.Storage
+
+	// OnProvision hooks run in order after storage is provisioned
+	OnProvision []Hook
+
+	// OnDelete hooks run in order before storage is purged
+	OnDelete []Hook
+}
+
+// Provision a tenant: it is registered, then its storage and other resources are prepared before it is made active.
+// A tenant failing provisioning remains registered as Provisioning so that it can be deleted.
+func (m *Manager) Provision(ctx context.Context, t Tenant) (Tenant, error) {
+	if !tenant.Valid(t.Name) {
+		return t, tenant.ErrInvalidName
+	}
+
+	t.Status = Provisioning
+
+	var err error
+	t, err = m.Registry.Create(ctx, t)
+	if err != nil {
+		return t, err
+	}
+
+	var tctx = tenant.Set(ctx, t.Name)
+
+	if p, ok := m.Storage.(storage.Provisioner); ok {
+		err = p.Provision(tctx)
+	}
+
+	for i := 0; err == nil && i < len(m.OnProvision); i++ {
+		err = m.OnProvision[i](tctx, t)
+	}
+
+	if err == nil {
+		t.Status = Active
+		err = m.Registry.Update(ctx, t)
+	}
+
+	return t, err
+}
+
+// Suspend a tenant; requests to it are refused until it is resumed
+func (m *Manager) Suspend(ctx context.Context, name string) (Tenant, error) {
+	return m.status(ctx, name, Suspended)
+}
+
+// Resume a suspended tenant
+func (m *Manager) Resume(ctx context.Context, name string) (Tenant, error) {
+	return m.status(ctx, name, Active)
+}
+
+// status changes the status of a tenant
+func (m *Manager) status(ctx context.Context, name string, s Status) (Tenant, error) {
+	var t, err = m.Registry.Get(ctx, name)
+	if err == nil {
+		t.Status = s
+		err = m.Registry.Update(ctx, t)
+	}
+
+	return t, err
+}
+
+// Delete a tenant after purging its resources and storage
+func (m *Manager) Delete(ctx context.Context, name string) error {
+	var t, err = m.Registry.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// never purge storage of an empty or unsafe tenant name, which would resolve outside of its space
+	if !tenant.Valid(t.Name) {
+		return tenant.ErrInvalidName
+	}
+
+	var tctx = tenant.Set(ctx, t.Name)
+
+	for i := 0; err == nil && i < len(m.OnDelete); i++ {
+		err = m.OnDelete[i](tctx, t)
+	}
+
+	if p, ok := m.Storage.(storage.Purger); ok && err == nil {
+		err = p.Purge(tctx)
+	}
+
+	if err == nil {
+		err = m.Registry.Delete(ctx, name)
+	}
+
+	return err
+}
+
+// Lookup of active tenants for tenant middlewares; suspended tenants are refused with tenant.ErrSuspended
+func (m *Manager) Lookup() tenant.Lookup {
+	return func(ctx context.Context, name string) (bool, error) {
+		var t, err = m.Registry.Get(ctx, name)
+		if err == repository.ErrNotFound {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+
+		switch t.Status {
+		case Active:
+			return true, nil
+		case Suspended:
+			return false, tenant.ErrSuspended
+		}
+
+		return false, nil
+	}
+}
+
+// StorageLimit returns the StorageQuota of tenants, for asset.Manager; tenants without it are unlimited
+func (m *Manager) StorageLimit() func(ctx context.Context, tenant string) (int64, error) {
+	return func(ctx context.Context, tenant string) (int64, error) {
+		return m.quota(ctx, tenant, StorageQuota)
+	}
+}
+
+// RecordsLimit returns the RecordsQuota of tenants per entity, for quota.Repo; entities without it are unlimited
+func (m *Manager) RecordsLimit() func(ctx context.Context, tenant, entity string) (int64, error) {
+	return func(ctx context.Context, tenant, entity string) (int64, error) {
+		return m.quota(ctx, tenant, RecordsQuota+entity)
+	}
+}
+
+// quota of a tenant by key
+func (m *Manager) quota(ctx context.Context, name, key string) (int64, error) {
+	var t, err = m.Registry.Get(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Quotas[key], nil
+}
@@ -0,0 +1,190 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields/entity"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// Status of a tenant
+type Status string
+
+const (
+	// Provisioning while the resources of a tenant are being created
+	Provisioning = Status("provisioning")
+
+	// Active tenants are served
+	Active = Status("active")
+
+	// Suspended tenants are kept but not served
+	Suspended = Status("suspended")
+)
+
+// Tenant record
+type Tenant struct {
+	ID string `json:"id"`
+
+	// Name of the tenant as set in contexts (see tenant.Get); must satisfy tenant.Valid
+	Name string `json:"name"`
+
+	Status Status `json:"status"`
+
+	// Quotas are limits of resources, example "storage_bytes": 1073741824
+	Quotas map[string]int64 `json:"quotas,omitempty"`
+
+	// Config is free-form settings of the tenant
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+// Entity of tenant records
+var Entity = entity.Spec{
+	EntityName: "tenant",
+	Desc:       "Tenants served by the application",
+	FieldSpecs: []entity.FieldSpec{
+		{Name: "id", Kind: types.String},
+		{Name: "name", Kind: types.String},
+		{Name: "status", Kind: types.String},
+		{Name: "quotas", Kind: types.JSON},
+		{Name: "config", Kind: types.JSON},
+	},
+	IndexSpecs: []entity.IndexSpec{
+		{Fields: []string{"name"}, Unique: true},
+	},
+}
+
+// Registry of tenants, identified by name
+type Registry interface {
+	// Get a tenant by name; repository.ErrNotFound if there is none
+	Get(ctx context.Context, name string) (Tenant, error)
+
+	// List all tenants by name
+	List(ctx context.Context) ([]Tenant, error)
+
+	// Create a tenant; repository.ErrConflict if the name is taken
+	Create(ctx context.Context, t Tenant) (Tenant, error)
+
+	// Update a tenant found by name
+	Update(ctx context.Context, t Tenant) error
+
+	// Delete a tenant by name
+	Delete(ctx context.Context, name string) error
+}
+
+// Repo is a registry persisting tenants as records of Entity in a repository. The repository must not be scoped to
+// tenants itself (see tenancy.Repo) as the registry spans all of them.
+type Repo struct {
+	repo repository.Repositorium
+}
+
+// New registry persisting tenants in a repository
+func New(repo repository.Repositorium) *Repo {
+	return &Repo{repo: repo}
+}
+
+// Get a tenant by name
+func (r *Repo) Get(ctx context.Context, name string) (Tenant, error) {
+	var l, err = r.repo.List(ctx, Entity, repository.Pagination{Limit: 1}, repository.Condition{
+		Attribute: "name",
+		Operator:  repository.Equals,
+		Value:     name,
+	})
+
+	if err == nil && len(l) == 0 {
+		err = repository.ErrNotFound
+	}
+
+	if err != nil {
+		return Tenant{}, err
+	}
+
+	return FromValues(&l[0])
+}
+
+// List all tenants by name
+func (r *Repo) List(ctx context.Context) ([]Tenant, error) {
+	var l, err = r.repo.List(ctx, Entity, repository.Pagination{Order: []repository.OrderBy{{Attribute: "name"}}})
+	if err != nil {
+		return nil, err
+	}
+
+	var tenants = make([]Tenant, len(l))
+	for i := range l {
+		tenants[i], err = FromValues(&l[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tenants, nil
+}
+
+// Create a tenant
+func (r *Repo) Create(ctx context.Context, t Tenant) (Tenant, error) {
+	var _, err = r.Get(ctx, t.Name)
+	if err == nil {
+		return t, repository.ErrConflict
+	} else if err != repository.ErrNotFound {
+		return t, err
+	}
+
+	t.ID, err = r.repo.Create(ctx, Entity, t.Values())
+
+	return t, err
+}
+
+// Update a tenant found by name
+func (r *Repo) Update(ctx context.Context, t Tenant) error {
+	var existing, err = r.Get(ctx, t.Name)
+	if err == nil {
+		err = r.repo.Update(ctx, Entity, existing.ID, t.Values())
+	}
+
+	return err
+}
+
+// Delete a tenant by name
+func (r *Repo) Delete(ctx context.Context, name string) error {
+	var t, err = r.Get(ctx, name)
+	if err == nil {
+		err = r.repo.Delete(ctx, Entity, t.ID)
+	}
+
+	return err
+}
+
+// Values of a tenant record, without id
+func (t Tenant) Values() *values.Values {
+	var vals = values.FromMap(map[string]interface{}{
+		"name":   t.Name,
+		"status": string(t.Status),
+	})
+
+	if t.Quotas != nil {
+		vals.Set("quotas", t.Quotas)
+	}
+
+	if t.Config != nil {
+		vals.Set("config", t.Config)
+	}
+
+	return vals
+}
+
+// FromValues reads a tenant record; values are converted through json as drivers return json fields and pointers
+// differently
+func FromValues(vals *values.Values) (Tenant, error) {
+	var (
+		t      Tenant
+		b, err = json.Marshal(vals.ToMap())
+	)
+
+	if err == nil {
+		err = json.Unmarshal(b, &t)
+	}
+
+	return t, err
+}
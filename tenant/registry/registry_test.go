@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fluxynet/gocipe/repository"
+	"github.com/fluxynet/gocipe/repository/repotest"
+	"github.com/fluxynet/gocipe/tenant"
+)
+
+// disk records storage operations per tenant
+type disk struct {
+	provisioned map[string]bool
+	deleted     map[string]string
+}
+
+func (d *disk) Store(ctx context.Context, filename string, contents []byte) error {
+	return nil
+}
+
+func (d *disk) Delete(ctx context.Context, path string) error {
+	d.deleted[tenant.Get(ctx)] = path
+	return nil
+}
+
+func (d *disk) Purge(ctx context.Context) error {
+	d.deleted[tenant.Get(ctx)] = ""
+	return nil
+}
+
+func (d *disk) Provision(ctx context.Context) error {
+	d.provisioned[tenant.Get(ctx)] = true
+	return nil
+}
+
+func TestManager(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		storage = &disk{provisioned: map[string]bool{}, deleted: map[string]string{}}
+		schemas []string
+		m       = Manager{
+			Registry: New(&repotest.Memory{}),
+			Storage:  storage,
+			OnProvision: []Hook{func(ctx context.Context, t Tenant) error {
+				schemas = append(schemas, tenant.Get(ctx))
+				return nil
+			}},
+		}
+		lookup = m.Lookup()
+	)
+
+	var acme, err = m.Provision(ctx, Tenant{Name: "acme", Quotas: map[string]int64{"users": 10}})
+	if err != nil || acme.ID == "" || acme.Status != Active {
+		t.Fatalf("Provision() = %v, %v", acme, err)
+	}
+
+	if !storage.provisioned["acme"] || len(schemas) != 1 || schemas[0] != "acme" {
+		t.Errorf("Provision() did not prepare storage and schema: %v, %v", storage.provisioned, schemas)
+	}
+
+	if _, err = m.Provision(ctx, Tenant{Name: "acme"}); err != repository.ErrConflict {
+		t.Errorf("Provision() of existing tenant error = %v, want %v", err, repository.ErrConflict)
+	}
+
+	if _, err = m.Provision(ctx, Tenant{Name: "../etc"}); err != tenant.ErrInvalidName {
+		t.Errorf("Provision() of invalid name error = %v, want %v", err, tenant.ErrInvalidName)
+	}
+
+	if got, _ := m.Registry.Get(ctx, "acme"); got.Quotas["users"] != 10 || got.Status != Active {
+		t.Errorf("Get() = %v", got)
+	}
+
+	if ok, err := lookup(ctx, "acme"); !ok || err != nil {
+		t.Errorf("Lookup() of active tenant = %v, %v", ok, err)
+	}
+
+	m.Suspend(ctx, "acme")
+	if ok, err := lookup(ctx, "acme"); ok || err != tenant.ErrSuspended {
+		t.Errorf("Lookup() of suspended tenant = %v, %v", ok, err)
+	}
+
+	m.Resume(ctx, "acme")
+	if ok, _ := lookup(ctx, "acme"); !ok {
+		t.Errorf("Lookup() of resumed tenant = false")
+	}
+
+	if ok, err := lookup(ctx, "globex"); ok || err != nil {
+		t.Errorf("Lookup() of unknown tenant = %v, %v", ok, err)
+	}
+
+	if err = m.Delete(ctx, "acme"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+
+	if path, ok := storage.deleted["acme"]; !ok || path != "" {
+		t.Errorf("Delete() did not purge storage of tenant: %v", storage.deleted)
+	}
+
+	if _, err = m.Registry.Get(ctx, "acme"); err != repository.ErrNotFound {
+		t.Errorf("Get() of deleted tenant error = %v", err)
+	}
+
+	var failing = errors.New("schema failed")
+	m.OnProvision = []Hook{func(ctx context.Context, t Tenant) error { return failing }}
+
+	if _, err = m.Provision(ctx, Tenant{Name: "initech"}); err != failing {
+		t.Errorf("Provision() error = %v, want %v", err, failing)
+	}
+
+	if ok, _ := lookup(ctx, "initech"); ok {
+		t.Errorf("Lookup() of tenant which failed provisioning = true")
+	}
+}
@@ -0,0 +1,157 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/fluxynet/gocipe/repository"
+)
+
+var (
+	// ErrInvalidName when a tenant name contains characters other than lowercase letters, digits, - and _; a kind of
+	// repository.ErrInvalid
+	ErrInvalidName = repository.NewError(repository.ErrInvalid, "invalid tenant name")
+
+	// ErrSuspended when a tenant exists but is suspended
+	ErrSuspended = errors.New("tenant suspended")
+)
+
+// Lookup checks if a tenant exists; it returns ErrSuspended if the tenant exists but must not be served
+type Lookup func(ctx context.Context, name string) (bool, error)
+
+// Names is a lookup of a fixed list of tenants
+func Names(names ...string) Lookup {
+	var m = make(map[string]bool, len(names))
+	for _, n := range names {
+		m[n] = true
+	}
+
+	return func(ctx context.Context, name string) (bool, error) {
+		return m[name], nil
+	}
+}
+
+// Valid checks that a tenant name is safe to use in paths, hosts and database names: 1 to 63 lowercase letters, digits,
+// - and _, starting with a letter or digit
+func Valid(name string) bool {
+	if name == "" || len(name) > 63 || name[0] == '-' || name[0] == '_' {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		var c = name[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolver extracts a tenant name from a request, returning the request to pass on or a non-zero status to reject it
+type resolver func(r *http.Request) (name string, req *http.Request, status int)
+
+// middleware setting the tenant resolved from requests, after validating it and checking that it exists; responds 400
+// if the name is missing or invalid, 404 if the tenant does not exist and 403 if it is suspended
+func middleware(resolve resolver, lookup Lookup) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		var fn = func(w http.ResponseWriter, r *http.Request) {
+			var name, req, status = resolve(r)
+
+			if status == 0 && !Valid(name) {
+				status = http.StatusBadRequest
+			}
+
+			if status == 0 {
+				var ok, err = lookup(r.Context(), name)
+				if errors.Is(err, ErrSuspended) {
+					status = http.StatusForbidden
+				} else if err != nil {
+					status = http.StatusInternalServerError
+				} else if !ok {
+					status = http.StatusNotFound
+				}
+			}
+
+			if status != 0 {
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+
+			next.ServeHTTP(w, req.WithContext(Set(req.Context(), name)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Header middleware to set tenant from a request header, example X-Tenant
+func Header(header string, lookup Lookup) func(next http.Handler) http.Handler {
+	return middleware(func(r *http.Request) (string, *http.Request, int) {
+		return r.Header.Get(header), r, 0
+	}, lookup)
+}
+
+// Subdomain middleware to set tenant from the subdomain of a domain, example acme for acme.example.com with domain
+// example.com
+func Subdomain(domain string, lookup Lookup) func(next http.Handler) http.Handler {
+	var suffix = "." + strings.ToLower(domain)
+
+	return middleware(func(r *http.Request) (string, *http.Request, int) {
+		var host = strings.ToLower(r.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if !strings.HasSuffix(host, suffix) {
+			return "", r, http.StatusBadRequest
+		}
+
+		return strings.TrimSuffix(host, suffix), r, 0
+	}, lookup)
+}
+
+// PathPrefix middleware to set tenant from the first segment of the request path, which is removed so that handlers
+// see the rest of the path, example /acme/products is routed as /products
+func PathPrefix(lookup Lookup) func(next http.Handler) http.Handler {
+	return middleware(func(r *http.Request) (string, *http.Request, int) {
+		var (
+			p    = strings.TrimPrefix(r.URL.Path, "/")
+			name = p
+			rest = "/"
+		)
+
+		if i := strings.Index(p, "/"); i != -1 {
+			name, rest = p[:i], p[i:]
+		}
+
+		var req = r.Clone(r.Context())
+		req.URL.Path = rest
+		req.URL.RawPath = ""
+
+		return name, req, 0
+	}, lookup)
+}
+
+// BearerClaim middleware to set tenant from a claim of the JWT bearer token of the Authorization header; the token is
+// checked by verify first and the request is rejected with 401 if it is missing or fails verification
+func BearerClaim(claim string, verify Verifier, lookup Lookup) func(next http.Handler) http.Handler {
+	return middleware(func(r *http.Request) (string, *http.Request, int) {
+		var auth = r.Header.Get("Authorization")
+		if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+			return "", r, http.StatusUnauthorized
+		}
+
+		var claims, err = verify(auth[7:])
+		if err != nil {
+			return "", r, http.StatusUnauthorized
+		}
+
+		var name, _ = claims[claim].(string)
+
+		return name, r, 0
+	}, lookup)
+}
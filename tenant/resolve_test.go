@@ -0,0 +1,236 @@
+package tenant
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// token signs claims as an HS256 JWT
+func token(secret, claims string) string {
+	var (
+		enc   = base64.RawURLEncoding
+		input = enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims))
+		mac   = hmac.New(sha256.New, []byte(secret))
+	)
+
+	mac.Write([]byte(input))
+
+	return input + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestValid(t *testing.T) {
+	tests := map[string]bool{
+		"acme":        true,
+		"acme-corp_2": true,
+		"":            false,
+		"Acme":        false,
+		"-acme":       false,
+		"..":          false,
+		"acme/../x":   false,
+		"acme.corp":   false,
+	}
+
+	for name, want := range tests {
+		if got := Valid(name); got != want {
+			t.Errorf("Valid(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestMiddlewares(t *testing.T) {
+	var (
+		lookup = Names("acme")
+		verify = HS256([]byte("secret"))
+	)
+
+	tests := []struct {
+		name       string
+		middleware func(next http.Handler) http.Handler
+		request    func() *http.Request
+		wantStatus int
+		wantTenant string
+		wantPath   string
+	}{
+		{
+			name:       "Header",
+			middleware: Header("X-Tenant", lookup),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			},
+			wantStatus: http.StatusOK,
+			wantTenant: "acme",
+			wantPath:   "/products",
+		},
+		{
+			name:       "Header missing",
+			middleware: Header("X-Tenant", lookup),
+			request:    func() *http.Request { return httptest.NewRequest("GET", "/products", nil) },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Header path traversal",
+			middleware: Header("X-Tenant", lookup),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("X-Tenant", "../etc")
+				return r
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Header unknown tenant",
+			middleware: Header("X-Tenant", lookup),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("X-Tenant", "globex")
+				return r
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Header suspended tenant",
+			middleware: Header("X-Tenant", func(ctx context.Context, name string) (bool, error) {
+				return false, ErrSuspended
+			}),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Header suspended tenant wrapped",
+			middleware: Header("X-Tenant", func(ctx context.Context, name string) (bool, error) {
+				return false, fmt.Errorf("lookup %s: %w", name, ErrSuspended)
+			}),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Subdomain",
+			middleware: Subdomain("example.com", lookup),
+			request:    func() *http.Request { return httptest.NewRequest("GET", "http://ACME.example.com:8080/products", nil) },
+			wantStatus: http.StatusOK,
+			wantTenant: "acme",
+			wantPath:   "/products",
+		},
+		{
+			name:       "Subdomain of another domain",
+			middleware: Subdomain("example.com", lookup),
+			request:    func() *http.Request { return httptest.NewRequest("GET", "http://acme.example.org/products", nil) },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Nested subdomain",
+			middleware: Subdomain("example.com", lookup),
+			request:    func() *http.Request { return httptest.NewRequest("GET", "http://www.acme.example.com/products", nil) },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Path prefix",
+			middleware: PathPrefix(lookup),
+			request:    func() *http.Request { return httptest.NewRequest("GET", "/acme/products/1", nil) },
+			wantStatus: http.StatusOK,
+			wantTenant: "acme",
+			wantPath:   "/products/1",
+		},
+		{
+			name:       "Path prefix only",
+			middleware: PathPrefix(lookup),
+			request:    func() *http.Request { return httptest.NewRequest("GET", "/acme", nil) },
+			wantStatus: http.StatusOK,
+			wantTenant: "acme",
+			wantPath:   "/",
+		},
+		{
+			name:       "Path prefix unknown tenant",
+			middleware: PathPrefix(lookup),
+			request:    func() *http.Request { return httptest.NewRequest("GET", "/globex/products", nil) },
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Bearer claim",
+			middleware: BearerClaim("tenant", verify, lookup),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("Authorization", "Bearer "+token("secret", `{"tenant":"acme"}`))
+				return r
+			},
+			wantStatus: http.StatusOK,
+			wantTenant: "acme",
+			wantPath:   "/products",
+		},
+		{
+			name:       "Bearer claim with invalid signature",
+			middleware: BearerClaim("tenant", verify, lookup),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("Authorization", "Bearer "+token("guess", `{"tenant":"acme"}`))
+				return r
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Bearer claim expired",
+			middleware: BearerClaim("tenant", verify, lookup),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("Authorization", "Bearer "+token("secret", `{"tenant":"acme","exp":1}`))
+				return r
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Bearer claim missing",
+			middleware: BearerClaim("tenant", verify, lookup),
+			request: func() *http.Request {
+				var r = httptest.NewRequest("GET", "/products", nil)
+				r.Header.Set("Authorization", "Bearer "+token("secret", `{"sub":"someone"}`))
+				return r
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "No bearer token",
+			middleware: BearerClaim("tenant", verify, lookup),
+			request:    func() *http.Request { return httptest.NewRequest("GET", "/products", nil) },
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotTenant, gotPath string
+				w                  = httptest.NewRecorder()
+			)
+
+			var h = tt.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotTenant, gotPath = Get(r.Context()), r.URL.Path
+			}))
+
+			h.ServeHTTP(w, tt.request())
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if gotTenant != tt.wantTenant || gotPath != tt.wantPath {
+				t.Errorf("handler got tenant %q path %q, want %q %q", gotTenant, gotPath, tt.wantTenant, tt.wantPath)
+			}
+		})
+	}
+}
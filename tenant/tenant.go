@@ -28,7 +28,7 @@ func Get(ctx context.Context) string {
 
 // Set assigns a tenant in context
 func Set(ctx context.Context, name string) context.Context {
-	return context.WithValue(ctx, tenant(Key), name)
+	return context.WithValue(ctx, tenant(Key), tenant(name))
 }
 
 // StaticHttp middleware to set tenant as a static value
@@ -0,0 +1,24 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	var ctx = context.Background()
+
+	if got := Get(ctx); got != "" {
+		t.Errorf("Get() without tenant = %q, want empty", got)
+	}
+
+	ctx = Set(ctx, "acme")
+	if got := Get(ctx); got != "acme" {
+		t.Errorf("Get() after Set() = %q, want acme", got)
+	}
+
+	ctx = context.WithValue(ctx, Key, "globex")
+	if got := Get(ctx); got != "acme" {
+		t.Errorf("Get() = %q, want tenant set by Set() only", got)
+	}
+}
@@ -0,0 +1,131 @@
+package fields
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Lookup returns the value of another field of the record being defaulted, or nil if absent
+type Lookup func(name string) interface{}
+
+// Default provides the value of a field when none has been supplied
+type Default interface {
+	// Value computes the default value
+	Value(lookup Lookup) interface{}
+
+	// String describes the default (used in documentation)
+	String() string
+}
+
+// Static is a default having a fixed value
+type Static struct {
+	V interface{}
+}
+
+// Value returns the static value
+func (s Static) Value(Lookup) interface{} {
+	return s.V
+}
+
+func (s Static) String() string {
+	if str, ok := s.V.(string); ok {
+		return strconv.Quote(str)
+	}
+
+	return fmt.Sprint(s.V)
+}
+
+// Computed is a default obtained by a function, typically based on the time or other fields
+type Computed struct {
+	// Name describes the function, example now()
+	Name string
+
+	// Func computes the value
+	Func func(lookup Lookup) interface{}
+}
+
+// Value calls the function to obtain the value
+func (c Computed) Value(lookup Lookup) interface{} {
+	return c.Func(lookup)
+}
+
+func (c Computed) String() string {
+	return c.Name
+}
+
+// Now defaults to the current time in RFC3339 format (UTC)
+func Now() Default {
+	return Computed{
+		Name: "now()",
+		Func: func(Lookup) interface{} {
+			return time.Now().UTC().Format(time.RFC3339)
+		},
+	}
+}
+
+// NowUnix defaults to the current time as seconds since epoch
+func NowUnix() Default {
+	return Computed{
+		Name: "unix()",
+		Func: func(Lookup) interface{} {
+			return time.Now().Unix()
+		},
+	}
+}
+
+// UUID defaults to a new random uuid
+func UUID() Default {
+	return Computed{
+		Name: "uuid()",
+		Func: func(Lookup) interface{} {
+			return uuid.NewString()
+		},
+	}
+}
+
+// SlugOf defaults to a slug derived from another string field, example "Hello World!" becomes "hello-world"
+func SlugOf(name string) Default {
+	return Computed{
+		Name: "slug(" + name + ")",
+		Func: func(lookup Lookup) interface{} {
+			switch v := lookup(name).(type) {
+			case string:
+				return Slug(v)
+			case *string:
+				if v != nil {
+					return Slug(*v)
+				}
+			}
+
+			return ""
+		},
+	}
+}
+
+// Slug returns a lowercase version of a string with runs of characters other than letters and digits replaced by -
+func Slug(s string) string {
+	var (
+		b    strings.Builder
+		dash bool
+	)
+
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() != 0 {
+				b.WriteRune('-')
+			}
+
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return b.String()
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"reflect"
+
 	"github.com/fluxynet/gocipe/types"
 	"github.com/fluxynet/gocipe/types/fields"
 )
@@ -11,6 +13,36 @@ type Entity interface {
 	Fields() fields.Fields
 }
 
+// Wrapper is an entity decorating another, such as a resource served via REST
+type Wrapper interface {
+	Unwrap() Entity
+}
+
+// As finds the first entity in the chain of wrapped entities that is assignable to target, and sets target to it.
+// Similar to errors.As, target must be a non-nil pointer to an interface or type; used to check optional interfaces.
+func As(e Entity, target interface{}) bool {
+	var (
+		val = reflect.ValueOf(target)
+		typ = val.Type().Elem()
+	)
+
+	for e != nil {
+		if reflect.TypeOf(e).AssignableTo(typ) {
+			val.Elem().Set(reflect.ValueOf(e))
+			return true
+		}
+
+		var w, ok = e.(Wrapper)
+		if !ok {
+			return false
+		}
+
+		e = w.Unwrap()
+	}
+
+	return false
+}
+
 // Entities is a collection of entities
 type Entities []Entity
 
@@ -60,3 +92,28 @@ func ID(name string) Entity {
 		),
 	}
 }
+
+// projected is an entity narrowed to some of its fields
+type projected struct {
+	Entity
+	fields fields.Fields
+}
+
+func (p projected) Fields() fields.Fields {
+	return p.fields
+}
+
+func (p projected) Unwrap() Entity {
+	return p.Entity
+}
+
+// Project an entity onto some of its fields, for repositories to fetch those only; optional interfaces of the entity
+// remain reachable through As
+func Project(e Entity, names ...string) (Entity, error) {
+	var f, err = e.Fields().Select(names...)
+	if err != nil {
+		return nil, err
+	}
+
+	return projected{Entity: e, fields: f}, nil
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Index declared on an entity, on one or more fields
+type Index struct {
+	// Name of the index; derived from fields if empty
+	Name string
+
+	// Fields making up the index, in order; prefix a field with - for descending order, example -created_at
+	Fields []string
+
+	// Unique means no two items can have the same values for the fields
+	Unique bool
+
+	// Text means the index is used for full-text search on the fields
+	Text bool
+
+	// ExpireAfter makes items expire after a duration based on the (single, time) field; supported by mongo only
+	ExpireAfter time.Duration
+}
+
+// IndexName returns the name of the index, derived from its fields if not set, example idx_customer_id_status
+func (i Index) IndexName() string {
+	if i.Name != "" {
+		return i.Name
+	}
+
+	var n = "idx"
+	switch {
+	case i.Unique:
+		n = "uniq"
+	case i.Text:
+		n = "text"
+	case i.ExpireAfter != 0:
+		n = "ttl"
+	}
+
+	for _, f := range i.Fields {
+		n += "_" + strings.TrimPrefix(f, "-")
+	}
+
+	return n
+}
+
+// IndexField is a field of an index along with its order
+type IndexField struct {
+	Name       string
+	Descending bool
+}
+
+// Columns returns the fields of the index along with their order
+func (i Index) Columns() []IndexField {
+	var c = make([]IndexField, len(i.Fields))
+
+	for j, f := range i.Fields {
+		c[j] = IndexField{Name: strings.TrimPrefix(f, "-"), Descending: strings.HasPrefix(f, "-")}
+	}
+
+	return c
+}
+
+// Indexed is an entity declaring indexes
+type Indexed interface {
+	Indexes() []Index
+}
+
+// IndexesOf an entity, if any; searchable entities also get a text index on their search fields unless they declare one
+func IndexesOf(e Entity) []Index {
+	var (
+		idx []Index
+		i   Indexed
+	)
+
+	if As(e, &i) {
+		idx = i.Indexes()
+	}
+
+	if s, ok := searchIndex(e, idx); ok {
+		idx = append(idx, s)
+	}
+
+	return idx
+}
@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidName is when an entity without a name is registered
+	ErrInvalidName = errors.New("invalid entity name")
+
+	// ErrDuplicateName is when an entity of the same name is already registered
+	ErrDuplicateName = errors.New("entity already registered")
+
+	// ErrDuplicatePath is when an entity is served on a path already used by another
+	ErrDuplicatePath = errors.New("path already registered")
+)
+
+// Described is an entity having a description
+type Described interface {
+	Description() string
+}
+
+// Routed is an entity served on a path
+type Routed interface {
+	Path() string
+}
+
+// Registry holds entity definitions, looked up by name. Registration is not thread-safe; register everything during
+// initialization before serving.
+type Registry struct {
+	entities Entities
+	names    map[string]Entity
+	paths    map[string]string
+}
+
+// NewRegistry returns a registry containing entities; panics on conflicts
+func NewRegistry(entities ...Entity) *Registry {
+	var r Registry
+
+	if err := r.Register(entities...); err != nil {
+		panic("failed to initialize registry: " + err.Error())
+	}
+
+	return &r
+}
+
+// Register one or more entities; none is registered if any of them conflicts
+func (r *Registry) Register(entities ...Entity) error {
+	var (
+		names = make(map[string]bool, len(entities))
+		paths = make(map[string]string, len(entities))
+	)
+
+	for _, e := range entities {
+		var name = e.Name()
+
+		if name == "" {
+			return ErrInvalidName
+		}
+
+		if _, ok := r.names[name]; ok || names[name] {
+			return fmt.Errorf("%w: %s", ErrDuplicateName, name)
+		}
+
+		names[name] = true
+
+		var p, ok = e.(Routed)
+		if !ok {
+			continue
+		}
+
+		var path = p.Path()
+
+		if other, ok := r.paths[path]; ok {
+			return fmt.Errorf("%w: %s used by %s and %s", ErrDuplicatePath, path, other, name)
+		}
+
+		if other, ok := paths[path]; ok {
+			return fmt.Errorf("%w: %s used by %s and %s", ErrDuplicatePath, path, other, name)
+		}
+
+		paths[path] = name
+	}
+
+	if r.names == nil {
+		r.names = make(map[string]Entity, len(entities))
+		r.paths = make(map[string]string, len(entities))
+	}
+
+	for _, e := range entities {
+		r.names[e.Name()] = e
+		r.entities.Add(e)
+	}
+
+	for path, name := range paths {
+		r.paths[path] = name
+	}
+
+	return nil
+}
+
+// Get an entity by name
+func (r *Registry) Get(name string) (Entity, bool) {
+	var e, ok = r.names[name]
+	return e, ok
+}
+
+// Has checks if an entity of that name is registered
+func (r *Registry) Has(name string) bool {
+	var _, ok = r.names[name]
+	return ok
+}
+
+// ByPath returns the entity served on a path
+func (r *Registry) ByPath(path string) (Entity, bool) {
+	var name, ok = r.paths[path]
+	if !ok {
+		return nil, false
+	}
+
+	return r.Get(name)
+}
+
+// Entities returns all registered entities in order of registration
+func (r *Registry) Entities() Entities {
+	var e = make(Entities, len(r.entities))
+	copy(e, r.entities)
+	return e
+}
+
+// Description of an entity if it has one
+func Description(e Entity) string {
+	var d Described
+	if As(e, &d) {
+		return d.Description()
+	}
+
+	return ""
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fluxynet/gocipe/types/fields"
+)
+
+type routed struct {
+	Entity
+	path string
+}
+
+func (r routed) Path() string {
+	return r.path
+}
+
+func (r routed) Unwrap() Entity {
+	return r.Entity
+}
+
+func TestRegistry_Register(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing Entities
+		args     Entities
+		wantErr  error
+		wantLen  int
+	}{
+		{
+			name:    "Empty",
+			args:    nil,
+			wantErr: nil,
+			wantLen: 0,
+		},
+		{
+			name:    "Valid",
+			args:    From(ID("foo"), ID("bar")),
+			wantErr: nil,
+			wantLen: 2,
+		},
+		{
+			name:    "No name",
+			args:    From(ID("")),
+			wantErr: ErrInvalidName,
+			wantLen: 0,
+		},
+		{
+			name:    "Duplicate name in args",
+			args:    From(ID("foo"), Partial("foo", fields.Fields{})),
+			wantErr: ErrDuplicateName,
+			wantLen: 0,
+		},
+		{
+			name:     "Duplicate name with existing",
+			existing: From(ID("foo")),
+			args:     From(ID("bar"), ID("foo")),
+			wantErr:  ErrDuplicateName,
+			wantLen:  1,
+		},
+		{
+			name:     "Duplicate path",
+			existing: From(routed{Entity: ID("foo"), path: "/items"}),
+			args:     From(routed{Entity: ID("bar"), path: "/items"}),
+			wantErr:  ErrDuplicatePath,
+			wantLen:  1,
+		},
+		{
+			name:    "Duplicate path in args",
+			args:    From(routed{Entity: ID("foo"), path: "/items"}, routed{Entity: ID("bar"), path: "/items"}),
+			wantErr: ErrDuplicatePath,
+			wantLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r = NewRegistry(tt.existing...)
+
+			var err = r.Register(tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if l := len(r.Entities()); l != tt.wantLen {
+				t.Errorf("Register() len = %d, want %d", l, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestAs(t *testing.T) {
+	var (
+		e = routed{Entity: ID("foo"), path: "/foo"}
+		w = routed{Entity: e, path: "/bar"}
+		p Routed
+		d Described
+	)
+
+	if !As(w, &p) || p.Path() != "/bar" {
+		t.Errorf("As() did not find outermost Routed")
+	}
+
+	if As(w, &d) {
+		t.Errorf("As() found Described where none exists")
+	}
+}
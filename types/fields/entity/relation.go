@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownRelationTarget is when a relation targets an entity which is not registered
+	ErrUnknownRelationTarget = errors.New("relation target not registered")
+)
+
+// Cardinality of a relation
+type Cardinality uint8
+
+const (
+	// BelongsTo means the entity holds the foreign key of a single target, example order belongs to customer
+	BelongsTo = Cardinality(0)
+
+	// HasMany means many targets hold the foreign key of the entity, example customer has many orders
+	HasMany = Cardinality(1)
+)
+
+// String representation (mainly for testing / debugging / logging)
+func (c Cardinality) String() string {
+	switch c {
+	case BelongsTo:
+		return "belongs-to"
+	case HasMany:
+		return "has-many"
+	}
+
+	return "??"
+}
+
+// Relation between an entity and a target entity
+type Relation struct {
+	// Name of the relation, used to embed related items, example customer
+	Name string
+
+	// Field holding the foreign key; on the entity for BelongsTo, on the target for HasMany
+	Field string
+
+	// Target entity name
+	Target string
+
+	// Cardinality of the relation
+	Cardinality Cardinality
+}
+
+// Related is an entity having relations with other entities
+type Related interface {
+	Relations() []Relation
+}
+
+// RelationsOf an entity, if any
+func RelationsOf(e Entity) []Relation {
+	var r Related
+	if As(e, &r) {
+		return r.Relations()
+	}
+
+	return nil
+}
+
+// RelationOf an entity by name
+func RelationOf(e Entity, name string) (Relation, bool) {
+	var rels = RelationsOf(e)
+
+	for i := range rels {
+		if rels[i].Name == name {
+			return rels[i], true
+		}
+	}
+
+	return Relation{}, false
+}
+
+// CheckRelations ensures that all relation targets of registered entities are also registered
+func (r *Registry) CheckRelations() error {
+	for _, e := range r.entities {
+		for _, rel := range RelationsOf(e) {
+			if !r.Has(rel.Target) {
+				return fmt.Errorf("%w: %s.%s targets %s", ErrUnknownRelationTarget, e.Name(), rel.Name, rel.Target)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package entity
+
+// Searchable is an entity whose string fields can be searched by full-text
+type Searchable interface {
+	SearchFields() []string
+}
+
+// SearchFieldsOf an entity; nil if it is not searchable
+func SearchFieldsOf(e Entity) []string {
+	var s Searchable
+	if As(e, &s) {
+		return s.SearchFields()
+	}
+
+	return nil
+}
+
+// searchIndex returns the text index implied by search fields, unless a text index is already declared
+func searchIndex(e Entity, idx []Index) (Index, bool) {
+	var f = SearchFieldsOf(e)
+	if len(f) == 0 {
+		return Index{}, false
+	}
+
+	for _, i := range idx {
+		if i.Text {
+			return Index{}, false
+		}
+	}
+
+	return Index{Name: "text_search", Fields: f, Text: true}, true
+}
@@ -0,0 +1,183 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/types/fields"
+)
+
+// Spec is an entity definition that can be decoded from json, used by command line tools
+//
+//	[{
+//	  "name": "order",
+//	  "fields": [{"name": "id", "kind": "string"}, {"name": "total", "kind": "float64", "default": 0}],
+//	  "indexes": [{"fields": ["customer_id"]}],
+//	  "search": ["reference", "notes"],
+//	  "relations": [{"name": "customer", "field": "customer_id", "target": "customer"}]
+//	}]
+type Spec struct {
+	EntityName    string         `json:"name"`
+	Desc          string         `json:"description,omitempty"`
+	FieldSpecs    []FieldSpec    `json:"fields"`
+	IndexSpecs    []IndexSpec    `json:"indexes,omitempty"`
+	SearchSpecs   []string       `json:"search,omitempty"`
+	RelationSpecs []RelationSpec `json:"relations,omitempty"`
+}
+
+// FieldSpec is a field definition of a Spec
+type FieldSpec struct {
+	Name    string      `json:"name"`
+	Kind    types.Type  `json:"kind"`
+	Default interface{} `json:"default,omitempty"`
+}
+
+// IndexSpec is an index definition of a Spec; expire_after is a duration such as 24h
+type IndexSpec struct {
+	Name        string   `json:"name,omitempty"`
+	Fields      []string `json:"fields"`
+	Unique      bool     `json:"unique,omitempty"`
+	Text        bool     `json:"text,omitempty"`
+	ExpireAfter string   `json:"expire_after,omitempty"`
+}
+
+// RelationSpec is a relation definition of a Spec; cardinality is either belongs-to (default) or has-many
+type RelationSpec struct {
+	Name        string `json:"name"`
+	Field       string `json:"field"`
+	Target      string `json:"target"`
+	Cardinality string `json:"cardinality,omitempty"`
+}
+
+// Name of the entity
+func (s Spec) Name() string {
+	return s.EntityName
+}
+
+// Description of the entity
+func (s Spec) Description() string {
+	return s.Desc
+}
+
+// Fields of the entity
+func (s Spec) Fields() fields.Fields {
+	var f fields.Fields
+
+	for _, fs := range s.FieldSpecs {
+		var field = fields.Field{Name: fs.Name, Kind: fs.Kind}
+		if fs.Default != nil {
+			field.Default = fields.Static{V: fs.Default}
+		}
+
+		f.Add(field)
+	}
+
+	return f
+}
+
+// Indexes of the entity
+func (s Spec) Indexes() []Index {
+	var idx = make([]Index, len(s.IndexSpecs))
+
+	for i, is := range s.IndexSpecs {
+		idx[i] = Index{Name: is.Name, Fields: is.Fields, Unique: is.Unique, Text: is.Text}
+		idx[i].ExpireAfter, _ = time.ParseDuration(is.ExpireAfter) // validated on decode
+	}
+
+	return idx
+}
+
+// SearchFields of the entity
+func (s Spec) SearchFields() []string {
+	return s.SearchSpecs
+}
+
+// Relations of the entity
+func (s Spec) Relations() []Relation {
+	var rels = make([]Relation, len(s.RelationSpecs))
+
+	for i, rs := range s.RelationSpecs {
+		rels[i] = Relation{Name: rs.Name, Field: rs.Field, Target: rs.Target}
+		if rs.Cardinality == HasMany.String() {
+			rels[i].Cardinality = HasMany
+		}
+	}
+
+	return rels
+}
+
+// validate checks that kinds, index and search fields are known
+func (s Spec) validate() error {
+	var known = make(map[string]types.Type, len(s.FieldSpecs))
+
+	for _, f := range s.FieldSpecs {
+		if !types.Valid(f.Kind) {
+			return fmt.Errorf("%s.%s: %w: %s", s.EntityName, f.Name, types.ErrInvalidValue, f.Kind)
+		}
+
+		known[f.Name] = f.Kind
+	}
+
+	for _, i := range s.IndexSpecs {
+		for _, f := range i.Fields {
+			var kind, ok = known[strings.TrimPrefix(f, "-")]
+
+			switch {
+			case !ok:
+				return fmt.Errorf("%s: index on unknown field %s", s.EntityName, f)
+			case kind == types.JSON:
+				return fmt.Errorf("%s: index on json field %s", s.EntityName, f)
+			case i.Text && kind != types.String:
+				return fmt.Errorf("%s: text index on non string field %s", s.EntityName, f)
+			}
+		}
+
+		if i.ExpireAfter == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(i.ExpireAfter); err != nil {
+			return fmt.Errorf("%s: index expiry: %w", s.EntityName, err)
+		}
+	}
+
+	for _, f := range s.SearchSpecs {
+		if known[f] != types.String {
+			return fmt.Errorf("%s: search on unknown or non string field %s", s.EntityName, f)
+		}
+	}
+
+	for _, r := range s.RelationSpecs {
+		if r.Cardinality != "" && r.Cardinality != BelongsTo.String() && r.Cardinality != HasMany.String() {
+			return fmt.Errorf("%s.%s: unknown cardinality %s", s.EntityName, r.Name, r.Cardinality)
+		}
+	}
+
+	return nil
+}
+
+// Decode entity specs from a json list, checking for conflicts
+func Decode(r io.Reader) (*Registry, error) {
+	var specs []Spec
+
+	if err := json.NewDecoder(r).Decode(&specs); err != nil {
+		return nil, err
+	}
+
+	var reg Registry
+	for i := range specs {
+		if err := specs[i].validate(); err != nil {
+			return nil, err
+		}
+
+		if err := reg.Register(specs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &reg, reg.CheckRelations()
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   string
+		wantErr bool
+	}{
+		{
+			name:  "Indexes",
+			specs: `[{"name": "product", "fields": [{"name": "sku", "kind": "string"}, {"name": "stock", "kind": "int64"}], "indexes": [{"fields": ["sku", "-stock"]}, {"fields": ["sku"], "text": true}]}]`,
+		},
+		{
+			name:    "Index on unknown field",
+			specs:   `[{"name": "product", "fields": [{"name": "sku", "kind": "string"}], "indexes": [{"fields": ["code"]}]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Index on json field",
+			specs:   `[{"name": "product", "fields": [{"name": "attrs", "kind": "json"}], "indexes": [{"fields": ["attrs"]}]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Text index on number",
+			specs:   `[{"name": "product", "fields": [{"name": "stock", "kind": "int64"}], "indexes": [{"fields": ["stock"], "text": true}]}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(strings.NewReader(tt.specs)); (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,17 +1,25 @@
 package fields
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/fluxynet/gocipe/types"
 )
 
+var (
+	// ErrUnknownField is when a field is not part of a set
+	ErrUnknownField = errors.New("unknown field")
+)
+
 // Field as part of a set
 type Field struct {
-	Name string
-	Kind types.Type
-	prev *Field
-	next *Field
+	Name    string
+	Kind    types.Type
+	Default Default
+	prev    *Field
+	next    *Field
 }
 
 // Fields representing a field set
@@ -68,28 +76,55 @@ func (f *Fields) Unset(name string) *Fields {
 
 // Set a named Field kind
 func (f *Fields) Set(name string, kind types.Type) *Fields {
-	var node = &Field{Name: name, Kind: kind}
+	if n, ok := f.items[name]; ok { // list contains item, must replace kind only
+		n.Kind = kind
+		return nil
+	}
+
+	return f.Add(Field{Name: name, Kind: kind})
+}
+
+// Add a Field along with its attributes (such as Default); replaces an existing field of the same name
+func (f *Fields) Add(field Field) *Fields {
+	var node = &Field{Name: field.Name, Kind: field.Kind, Default: field.Default}
 
 	if f.head == nil { // list is empty
 		f.head = node
 		f.tail = f.head
-		f.items = map[string]*Field{name: f.head}
+		f.items = map[string]*Field{node.Name: f.head}
 		return nil
 	}
 
-	if _, ok := f.items[name]; ok { // list contains item, must replace
-		f.items[name].Kind = kind
+	if n, ok := f.items[node.Name]; ok { // list contains item, must replace
+		n.Kind = node.Kind
+		n.Default = node.Default
 		return nil
 	}
 
 	node.prev = f.tail
 	f.tail.next = node
 	f.tail = node
-	f.items[name] = node
+	f.items[node.Name] = node
 
 	return f
 }
 
+// Select returns the named fields only, in the order they are named; duplicates are ignored
+func (f Fields) Select(names ...string) (Fields, error) {
+	var s Fields
+
+	for _, name := range names {
+		var n, ok = f.items[name]
+		if !ok {
+			return Fields{}, fmt.Errorf("%w: %s", ErrUnknownField, name)
+		}
+
+		s.Add(*n)
+	}
+
+	return s, nil
+}
+
 // GetEmptyValues for fields consisting of pointers whereunto the data can be placed
 func (f Fields) GetEmptyValues() map[string]interface{} {
 	var (
@@ -107,11 +142,11 @@ func (f Fields) GetEmptyValues() map[string]interface{} {
 
 // TypeOf returns typeof a field
 func (f Fields) TypeOf(name string) types.Type {
-	if f.head == nil {
-		return types.Undefined
+	if x, ok := f.items[name]; ok {
+		return x.Kind
 	}
 
-	return f.items[name].Kind
+	return types.Undefined
 }
 
 // String representation (for debugging mainly)
@@ -190,7 +225,7 @@ func FromMap(m map[string]types.Type) Fields {
 func From(p ...Field) Fields {
 	var f Fields
 	for i := range p {
-		f.Set(p[i].Name, p[i].Kind)
+		f.Add(p[i])
 	}
 
 	return f
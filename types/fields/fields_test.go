@@ -1,6 +1,7 @@
 package fields
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/fluxynet/gocipe/types"
@@ -208,3 +209,63 @@ func TestFields_String(t *testing.T) {
 		})
 	}
 }
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Empty", s: "", want: ""},
+		{name: "Simple", s: "Hello World", want: "hello-world"},
+		{name: "Punctuation", s: "  Hello, World!! ", want: "hello-world"},
+		{name: "Digits", s: "Top 10 -- 2021", want: "top-10-2021"},
+		{name: "Unicode", s: "Île Maurice", want: "île-maurice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slug(tt.s); got != tt.want {
+				t.Errorf("Slug() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFields_Select(t *testing.T) {
+	var f = From(
+		Field{Name: "id", Kind: types.String},
+		Field{Name: "name", Kind: types.String},
+		Field{Name: "price", Kind: types.Float64},
+	)
+
+	tests := []struct {
+		name    string
+		names   []string
+		want    string
+		wantErr error
+	}{
+		{name: "None", names: nil, want: ""},
+		{name: "Ordered as named", names: []string{"price", "id"}, want: "price:float64, id:string"},
+		{name: "Duplicates", names: []string{"name", "name"}, want: "name:string"},
+		{name: "Unknown", names: []string{"id", "cost"}, wantErr: ErrUnknownField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, err = f.Select(tt.names...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Select() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if s := got.String(); s != tt.want {
+				t.Errorf("Select() = %s, want %s", s, tt.want)
+			}
+
+			if f.Length() != 3 {
+				t.Errorf("Select() modified original fields")
+			}
+		})
+	}
+}
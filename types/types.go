@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"errors"
 	"strconv"
 )
@@ -28,8 +29,21 @@ const (
 
 	// Float64 indicates native float64
 	Float64 = Type("float64")
+
+	// JSON indicates a raw json document, exposed as decoded structures (map, slice, string, number, bool or nil)
+	JSON = Type("json")
 )
 
+// Valid checks if a type is one of the known types
+func Valid(t Type) bool {
+	switch t {
+	case Bool, String, Int64, Float64, JSON:
+		return true
+	}
+
+	return false
+}
+
 // BoolFromString parses Bool
 func BoolFromString(s string) (bool, error) {
 	switch s {
@@ -52,11 +66,25 @@ func Float64FromString(s string) (float64, error) {
 	return strconv.ParseFloat(string(s), 64)
 }
 
+// JSONFromBytes decodes a raw json document; empty input yields nil
+func JSONFromBytes(b []byte) (interface{}, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, ErrInvalidValue
+	}
+
+	return v, nil
+}
+
 // Default for types
 func Default(t Type) interface{} {
 	switch t {
 	case Bool:
-		return true
+		return false
 	case String:
 		return ""
 	case Int64:
@@ -79,6 +107,8 @@ func New(t Type) interface{} {
 		return new(int64)
 	case Float64:
 		return new(float64)
+	case JSON:
+		return new([]byte)
 	}
 
 	return nil
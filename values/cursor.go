@@ -0,0 +1,63 @@
+package values
+
+// Cursor iterates over records which are fetched one at a time
+type Cursor interface {
+	// Next moves to the next record if available and returns true; returns false at the end or on error
+	Next() bool
+
+	// Value returns the current record
+	Value() Values
+
+	// Err returns the error which stopped iteration, if any
+	Err() error
+
+	// Close releases resources held by the cursor
+	Close() error
+}
+
+// NewSliceCursor returns a cursor over records already in memory
+func NewSliceCursor(list []Values) Cursor {
+	return &sliceCursor{list: list, current: -1}
+}
+
+type sliceCursor struct {
+	list    []Values
+	current int
+}
+
+func (c *sliceCursor) Next() bool {
+	if c.current+1 >= len(c.list) {
+		return false
+	}
+
+	c.current++
+	return true
+}
+
+func (c *sliceCursor) Value() Values {
+	return c.list[c.current]
+}
+
+func (c *sliceCursor) Err() error {
+	return nil
+}
+
+func (c *sliceCursor) Close() error {
+	return nil
+}
+
+// All reads the remaining records of a cursor and closes it
+func All(c Cursor) ([]Values, error) {
+	var list []Values
+
+	for c.Next() {
+		list = append(list, c.Value())
+	}
+
+	var err = c.Err()
+	if e := c.Close(); err == nil {
+		err = e
+	}
+
+	return list, err
+}
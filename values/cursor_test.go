@@ -0,0 +1,41 @@
+package values
+
+import (
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	var list = []Values{
+		*FromMap(map[string]interface{}{"id": "1"}),
+		*FromMap(map[string]interface{}{"id": "2"}),
+	}
+
+	tests := []struct {
+		name string
+		list []Values
+		want []string
+	}{
+		{name: "Empty", list: nil, want: nil},
+		{name: "Ordered", list: list, want: []string{"1", "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, err = All(NewSliceCursor(tt.list))
+			if err != nil {
+				t.Errorf("All() unexpected error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("All() len = %d, want %d", len(got), len(tt.want))
+				return
+			}
+
+			for i := range got {
+				if id := got[i].Get("id").String(); id != tt.want[i] {
+					t.Errorf("All()[%d] = %s, want %s", i, id, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/values"
+)
+
+// Func is a custom validator for a single value; a non-nil error message is reported against the field
+type Func func(v interface{}) error
+
+// Rule is a set of constraints applicable to a named field
+type Rule struct {
+	Name      string
+	Kind      types.Type
+	Required  bool
+	Minimum   *float64
+	Maximum   *float64
+	MinLength int
+	MaxLength int
+	Pattern   *regexp.Regexp
+	Enum      []interface{}
+	Funcs     []Func
+}
+
+// Constrained is an entity which declares validation rules for its fields
+type Constrained interface {
+	Rules() []Rule
+}
+
+// FieldError is a validation failure on a specific field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors is a list of validation failures
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	var s = make([]string, len(e))
+	for i := range e {
+		s[i] = e[i].Field + ": " + e[i].Message
+	}
+
+	return "validation failed: " + strings.Join(s, "; ")
+}
+
+// Validate values against rules. When partial, fields which are absent are not checked (typically PATCH).
+// Returns Errors when at least one constraint fails, nil otherwise.
+func Validate(vals *values.Values, rules []Rule, partial bool) error {
+	var errs Errors
+
+	for i := range rules {
+		var (
+			r = rules[i]
+			v *values.Value
+		)
+
+		if vals != nil {
+			v = vals.Get(r.Name)
+		}
+
+		if v == nil && partial {
+			continue
+		}
+
+		if v == nil || isEmpty(v.Value) {
+			if r.Required {
+				errs = append(errs, FieldError{Field: r.Name, Message: "is required"})
+			}
+
+			continue
+		}
+
+		for _, msg := range r.check(v.Value) {
+			errs = append(errs, FieldError{Field: r.Name, Message: msg})
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// check a value that is present against the rule, returning failure messages
+func (r Rule) check(v interface{}) []string {
+	var msgs []string
+
+	if n, ok := toFloat(v); ok {
+		if r.Minimum != nil && n < *r.Minimum {
+			msgs = append(msgs, "must be greater than or equal to "+formatFloat(*r.Minimum))
+		}
+
+		if r.Maximum != nil && n > *r.Maximum {
+			msgs = append(msgs, "must be less than or equal to "+formatFloat(*r.Maximum))
+		}
+	}
+
+	if s, ok := toString(v); ok {
+		var l = utf8.RuneCountInString(s)
+
+		if r.MinLength != 0 && l < r.MinLength {
+			msgs = append(msgs, "must be at least "+strconv.Itoa(r.MinLength)+" characters long")
+		}
+
+		if r.MaxLength != 0 && l > r.MaxLength {
+			msgs = append(msgs, "must be at most "+strconv.Itoa(r.MaxLength)+" characters long")
+		}
+
+		if r.Pattern != nil && !r.Pattern.MatchString(s) {
+			msgs = append(msgs, "must match pattern "+r.Pattern.String())
+		}
+	}
+
+	if len(r.Enum) != 0 && !inEnum(v, r.Enum) {
+		msgs = append(msgs, fmt.Sprintf("must be one of %v", r.Enum))
+	}
+
+	for _, fn := range r.Funcs {
+		if err := fn(v); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+
+	return msgs
+}
+
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	var s, ok = toString(v)
+	return ok && s == ""
+}
+
+func inEnum(v interface{}, enum []interface{}) bool {
+	var n, isNum = toFloat(v)
+
+	for _, e := range enum {
+		if isNum {
+			if m, ok := toFloat(e); ok && m == n {
+				return true
+			}
+		} else if reflect.DeepEqual(e, deref(v)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deref values which are pointers (as scanned from some repositories)
+func deref(v interface{}) interface{} {
+	switch x := v.(type) {
+	case *string:
+		return *x
+	case *bool:
+		return *x
+	case *int64:
+		return *x
+	case *float64:
+		return *x
+	}
+
+	return v
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := deref(v).(type) {
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+
+	return 0, false
+}
+
+func toString(v interface{}) (string, bool) {
+	var s, ok = deref(v).(string)
+	return s, ok
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
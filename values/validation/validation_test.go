@@ -0,0 +1,158 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/fluxynet/gocipe/types"
+	"github.com/fluxynet/gocipe/values"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestValidate(t *testing.T) {
+	type args struct {
+		vals    *values.Values
+		rules   []Rule
+		partial bool
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want Errors
+	}{
+		{
+			name: "No rules",
+			args: args{
+				vals:  values.FromMap(map[string]interface{}{"name": "foo"}),
+				rules: nil,
+			},
+			want: nil,
+		},
+		{
+			name: "Required missing",
+			args: args{
+				vals: values.FromMap(map[string]interface{}{}),
+				rules: []Rule{
+					{Name: "name", Kind: types.String, Required: true},
+				},
+			},
+			want: Errors{{Field: "name", Message: "is required"}},
+		},
+		{
+			name: "Required empty string",
+			args: args{
+				vals: values.FromMap(map[string]interface{}{"name": ""}),
+				rules: []Rule{
+					{Name: "name", Kind: types.String, Required: true},
+				},
+			},
+			want: Errors{{Field: "name", Message: "is required"}},
+		},
+		{
+			name: "Required missing partial",
+			args: args{
+				vals: values.FromMap(map[string]interface{}{}),
+				rules: []Rule{
+					{Name: "name", Kind: types.String, Required: true},
+				},
+				partial: true,
+			},
+			want: nil,
+		},
+		{
+			name: "Lengths",
+			args: args{
+				vals: values.FromMap(map[string]interface{}{"a": "ab", "b": "abcdef"}),
+				rules: []Rule{
+					{Name: "a", Kind: types.String, MinLength: 3},
+					{Name: "b", Kind: types.String, MaxLength: 5},
+				},
+			},
+			want: Errors{
+				{Field: "a", Message: "must be at least 3 characters long"},
+				{Field: "b", Message: "must be at most 5 characters long"},
+			},
+		},
+		{
+			name: "Range",
+			args: args{
+				vals: values.FromMap(map[string]interface{}{"age": 12, "price": float64(100.5)}),
+				rules: []Rule{
+					{Name: "age", Kind: types.Int64, Minimum: float(18)},
+					{Name: "price", Kind: types.Float64, Maximum: float(100)},
+				},
+			},
+			want: Errors{
+				{Field: "age", Message: "must be greater than or equal to 18"},
+				{Field: "price", Message: "must be less than or equal to 100"},
+			},
+		},
+		{
+			name: "Pattern and enum",
+			args: args{
+				vals: values.FromMap(map[string]interface{}{"code": "mu1", "status": "gone"}),
+				rules: []Rule{
+					{Name: "code", Kind: types.String, Pattern: regexp.MustCompile(`^[A-Z]{2}$`)},
+					{Name: "status", Kind: types.String, Enum: []interface{}{"active", "inactive"}},
+				},
+			},
+			want: Errors{
+				{Field: "code", Message: "must match pattern ^[A-Z]{2}$"},
+				{Field: "status", Message: "must be one of [active inactive]"},
+			},
+		},
+		{
+			name: "Numeric enum",
+			args: args{
+				vals: values.FromMap(map[string]interface{}{"level": 2}),
+				rules: []Rule{
+					{Name: "level", Kind: types.Int64, Enum: []interface{}{1, 2, 3}},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "Custom func",
+			args: args{
+				vals: values.FromMap(map[string]interface{}{"email": "foo"}),
+				rules: []Rule{
+					{Name: "email", Kind: types.String, Funcs: []Func{
+						func(v interface{}) error {
+							return errors.New("must be a valid email")
+						},
+					}},
+				},
+			},
+			want: Errors{{Field: "email", Message: "must be a valid email"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err = Validate(tt.args.vals, tt.args.rules, tt.args.partial)
+
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+
+			var got Errors
+			if !errors.As(err, &got) {
+				t.Errorf("Validate() error = %v, want Errors", err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate()\n\tgot  = %v\n\twant = %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -139,20 +139,40 @@ func (v *Values) Unset(name string) *Values {
 		return v
 	}
 
-	if d.prev == nil { // only one in list
-		v.head = nil
-		v.tail = nil
-		delete(v.items, name)
-		return v
+	if d.prev == nil { // first in list
+		v.head = d.next
+	} else {
+		d.prev.next = d.next
+	}
+
+	if d.next == nil { // last in list
+		v.tail = d.prev
+	} else {
+		d.next.prev = d.prev
 	}
 
-	d.prev.next = d.next
 	d.prev = nil
 	d.next = nil
+	delete(v.items, name)
 
 	return v
 }
 
+// Clone returns a copy of the value set; values themselves are not copied
+func (v *Values) Clone() *Values {
+	var (
+		c  Values
+		it = v.Iterator()
+	)
+
+	for it.Next() {
+		var x = it.Value()
+		c.Set(x.Name, x.Value)
+	}
+
+	return &c
+}
+
 // ToMap Returns a map[string]interface{} representation of the list
 func (v *Values) ToMap() map[string]interface{} {
 	var m = make(map[string]interface{}, len(v.items))
@@ -229,13 +249,8 @@ func FromJSON(r io.ReadCloser, f fields.Fields, allowPartial bool) (*Values, err
 		var v, ok = m[i.Name]
 		var x interface{}
 
-		if ok {
-			// will deal with it later
-		} else if allowPartial {
-			continue // it is missing but document is partial, skip
-		} else {
-			vals.Set(i.Name, types.Default(i.Kind)) // set it to default
-			continue
+		if !ok {
+			continue // missing values are completed once all others are known, unless document is partial
 		}
 
 		switch i.Kind {
@@ -252,6 +267,8 @@ func FromJSON(r io.ReadCloser, f fields.Fields, allowPartial bool) (*Values, err
 			x, err = strconv.Atoi(string(v))
 		case types.Float64:
 			x, err = strconv.ParseFloat(string(v), 64)
+		case types.JSON:
+			x, err = types.JSONFromBytes(v)
 		}
 
 		if err != nil {
@@ -261,9 +278,52 @@ func FromJSON(r io.ReadCloser, f fields.Fields, allowPartial bool) (*Values, err
 		vals.Set(i.Name, x)
 	}
 
+	if !allowPartial {
+		vals.Complete(f)
+	}
+
 	return &vals, nil
 }
 
+// Complete sets values for fields which are missing: their Default if they declare one, the zero value of their kind
+// otherwise. Presence of values should be checked before, as a completed field can no longer be told apart.
+func (v *Values) Complete(f fields.Fields) *Values {
+	var it = f.Iterator()
+	for it.Next() {
+		var i = it.Field()
+		if i.Default == nil && v.Get(i.Name) == nil {
+			v.Set(i.Name, types.Default(i.Kind))
+		}
+	}
+
+	return v.ApplyDefaults(f)
+}
+
+// ApplyDefaults sets values for fields which are missing and declare a Default
+func (v *Values) ApplyDefaults(f fields.Fields) *Values {
+	var (
+		it     = f.Iterator()
+		lookup = func(name string) interface{} {
+			if x := v.Get(name); x != nil {
+				return x.Value
+			}
+
+			return nil
+		}
+	)
+
+	for it.Next() {
+		var i = it.Field()
+		if i.Default == nil || v.Get(i.Name) != nil {
+			continue
+		}
+
+		v.Set(i.Name, i.Default.Value(lookup))
+	}
+
+	return v
+}
+
 func (v *Values) String() string {
 	var (
 		s  []string
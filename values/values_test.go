@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/fluxynet/gocipe/types"
@@ -332,6 +333,45 @@ func TestFromJSON(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name: "json",
+			args: args{
+				r: `{"attrs": {"color": "red", "sizes": [1, 2]}, "empty": null}`,
+				f: fields.FromMap(map[string]types.Type{
+					"attrs": types.JSON,
+					"empty": types.JSON,
+				}),
+			},
+			want: FromMap(map[string]interface{}{
+				"attrs": map[string]interface{}{
+					"color": "red",
+					"sizes": []interface{}{float64(1), float64(2)},
+				},
+				"empty": nil,
+			}),
+			wantErr: false,
+		},
+		{
+			name: "defaults",
+			args: args{
+				r: `{"title": "Hello World!", "status": "draft"}`,
+				f: fields.From(
+					fields.Field{Name: "title", Kind: types.String},
+					fields.Field{Name: "slug", Kind: types.String, Default: fields.SlugOf("title")},
+					fields.Field{Name: "status", Kind: types.String, Default: fields.Static{V: "published"}},
+					fields.Field{Name: "views", Kind: types.Int64, Default: fields.Static{V: int64(1)}},
+					fields.Field{Name: "active", Kind: types.Bool},
+				),
+			},
+			want: FromMap(map[string]interface{}{
+				"title":  "Hello World!",
+				"slug":   "hello-world",
+				"status": "draft",
+				"views":  int64(1),
+				"active": false,
+			}),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -350,6 +390,29 @@ func TestFromJSON(t *testing.T) {
 	}
 }
 
+func TestValues_Complete(t *testing.T) {
+	var f = fields.From(
+		fields.Field{Name: "title", Kind: types.String},
+		fields.Field{Name: "stock", Kind: types.Int64},
+		fields.Field{Name: "slug", Kind: types.String, Default: fields.SlugOf("title")},
+	)
+
+	var got, err = FromJSON(io.NopCloser(bytes.NewReader([]byte(`{"title": "Hello"}`))), f, true)
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+
+	if got.Get("stock") != nil {
+		t.Errorf("FromJSON() of partial document = %v, want stock to be absent", got)
+	}
+
+	compareValues(t, got.Complete(f), FromMap(map[string]interface{}{
+		"title": "Hello",
+		"stock": 0,
+		"slug":  "hello",
+	}))
+}
+
 func Test_iterator_Next(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -428,3 +491,57 @@ func Test_iterator_Next(t *testing.T) {
 		})
 	}
 }
+
+func TestValues_Clone(t *testing.T) {
+	var v Values
+	v.Set("a", 1)
+	v.Set("b", 2)
+
+	var c = v.Clone()
+	v.Set("a", 3)
+	v.Set("c", 4)
+
+	if got := c.String(); got != `a:1, b:2` {
+		t.Errorf("Clone() affected by Set() = %s", got)
+	}
+}
+
+func TestValues_Unset(t *testing.T) {
+	tests := []struct {
+		name  string
+		unset []string
+		add   string
+		want  string
+	}{
+		{name: "Head", unset: []string{"a"}, want: `b:2, c:3`},
+		{name: "Middle", unset: []string{"b"}, want: `a:1, c:3`},
+		{name: "Tail then add", unset: []string{"c"}, add: "d", want: `a:1, b:2, d:4`},
+		{name: "All", unset: []string{"b", "a", "c"}, want: ``},
+		{name: "Unknown", unset: []string{"x"}, want: `a:1, b:2, c:3`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Values
+			v.Set("a", 1)
+			v.Set("b", 2)
+			v.Set("c", 3)
+
+			for _, name := range tt.unset {
+				v.Unset(name)
+			}
+
+			if tt.add != "" {
+				v.Set(tt.add, 4)
+			}
+
+			if got := v.String(); got != tt.want {
+				t.Errorf("Unset() = %s, want %s", got, tt.want)
+			}
+
+			if v.Length() != strings.Count(tt.want, ":") {
+				t.Errorf("Unset() length = %d", v.Length())
+			}
+		})
+	}
+}